@@ -0,0 +1,103 @@
+package lambda
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrSkipped is reported for the records that were not processed because a previous record of the same ordered group
+// failed (Eg: the same message group of a FIFO queue).
+var ErrSkipped = errors.New("skipped after a previous record of the group failed")
+
+// processBatch processes the records of a batch, split in groups. The records of a group are processed in order and,
+// once one fails, the next ones fail with ErrSkipped. At most concurrency groups are processed at the same time.
+//
+// It returns the errors of the failed records, by index. Panics are reported as errors.
+func processBatch(concurrency int, groups [][]int, process func(i int) error) map[int]error {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	var (
+		mu       sync.Mutex
+		failures = make(map[int]error)
+		wg       sync.WaitGroup
+		sem      = make(chan struct{}, concurrency)
+	)
+	for _, group := range groups {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(group []int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			for j, i := range group {
+				err := safeProcess(process, i)
+				if err == nil {
+					continue
+				}
+				mu.Lock()
+				failures[i] = err
+				for _, skipped := range group[j+1:] {
+					failures[skipped] = ErrSkipped
+				}
+				mu.Unlock()
+				return
+			}
+		}(group)
+	}
+	wg.Wait()
+	return failures
+}
+
+func safeProcess(process func(i int) error, i int) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("panic: %v", p)
+		}
+	}()
+	return process(i)
+}
+
+// groupBy splits the indexes 0..n-1 in groups with the same key, keeping their order. Records with an empty key are
+// put in groups of their own.
+func groupBy(n int, key func(i int) string) [][]int {
+	var (
+		groups  [][]int
+		byGroup = make(map[string]int)
+	)
+	for i := 0; i < n; i++ {
+		k := key(i)
+		if k == "" {
+			groups = append(groups, []int{i})
+			continue
+		}
+		g, ok := byGroup[k]
+		if !ok {
+			g = len(groups)
+			byGroup[k] = g
+			groups = append(groups, nil)
+		}
+		groups[g] = append(groups[g], i)
+	}
+	return groups
+}
+
+// decodeMessage decodes the body of a record. Strings and byte slices receive the body as is, other types are decoded
+// from JSON.
+func decodeMessage[Msg any](body string) (Msg, error) {
+	var msg Msg
+	switch m := any(&msg).(type) {
+	case *string:
+		*m = body
+	case *[]byte:
+		*m = []byte(body)
+	default:
+		if err := json.Unmarshal([]byte(body), &msg); err != nil {
+			return msg, err
+		}
+	}
+	return msg, nil
+}
@@ -0,0 +1,76 @@
+package lambda
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProcessBatch(t *testing.T) {
+	failure := errors.New("failure")
+
+	t.Run("should skip the records of a group after a failure", func(t *testing.T) {
+		var processed []int
+		failures := processBatch(1, [][]int{{0, 1, 2}, {3}}, func(i int) error {
+			processed = append(processed, i)
+			if i == 1 {
+				return failure
+			}
+			return nil
+		})
+		assert.Equal(t, []int{0, 1, 3}, processed)
+		assert.Equal(t, map[int]error{1: failure, 2: ErrSkipped}, failures)
+	})
+
+	t.Run("should report panics as failures", func(t *testing.T) {
+		failures := processBatch(1, [][]int{{0}}, func(i int) error {
+			panic("boom")
+		})
+		assert.EqualError(t, failures[0], "panic: boom")
+	})
+
+	t.Run("should bound the number of groups processed at the same time", func(t *testing.T) {
+		var running, maxRunning atomic.Int32
+		processBatch(2, [][]int{{0}, {1}, {2}, {3}, {4}}, func(i int) error {
+			n := running.Add(1)
+			for {
+				m := maxRunning.Load()
+				if n <= m || maxRunning.CompareAndSwap(m, n) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			running.Add(-1)
+			return nil
+		})
+		assert.Equal(t, int32(2), maxRunning.Load())
+	})
+}
+
+func TestGroupBy(t *testing.T) {
+	t.Run("should group the records by key keeping their order", func(t *testing.T) {
+		keys := []string{"a", "", "b", "a", ""}
+		groups := groupBy(len(keys), func(i int) string { return keys[i] })
+		assert.Equal(t, [][]int{{0, 3}, {1}, {2}, {4}}, groups)
+	})
+}
+
+func TestDecodeMessage(t *testing.T) {
+	t.Run("should decode JSON", func(t *testing.T) {
+		msg, err := decodeMessage[map[string]int](`{"a":1}`)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]int{"a": 1}, msg)
+	})
+
+	t.Run("should keep the body as is for strings and byte slices", func(t *testing.T) {
+		s, err := decodeMessage[string]("not json")
+		assert.NoError(t, err)
+		assert.Equal(t, "not json", s)
+		b, err := decodeMessage[[]byte]("raw")
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("raw"), b)
+	})
+}
@@ -1,6 +1,10 @@
 package lambda
 
-import "context"
+import (
+	"context"
+
+	"github.com/jamillosantos/lambda/internal/resources"
+)
 
 // None is an empty struct used when we are not interested on the request or response body.
 type None struct{}
@@ -16,6 +20,15 @@ func (l *Context[Req]) SetLocal(key string, value any) *Context[Req] {
 	return l
 }
 
+// PutLocal stores the value in the locals. Unlike SetLocal, it does not return the Context, so both Context types
+// implement Locals.
+func (l *Context[Req]) PutLocal(key string, value any) {
+	if l.Locals == nil {
+		l.Locals = make(map[string]any)
+	}
+	l.Locals[key] = value
+}
+
 func (l *Context[Req]) GetLocal(key string) (any, bool) {
 	value, ok := l.Locals[key]
 	return value, ok
@@ -25,3 +38,23 @@ func (l *Context[Req]) UnsetLocal(key string) *Context[Req] {
 	delete(l.Locals, key)
 	return l
 }
+
+// ResourceReady reports whether the resource with the given name was started successfully.
+func (l *Context[Req]) ResourceReady(name string) bool {
+	return resources.Ready(l.Context, name)
+}
+
+// RequireResource starts the resource with the given name, and its dependencies, if it was not started yet. It is how
+// resources with LazyPolicy are started.
+func (l *Context[Req]) RequireResource(name string) error {
+	return resources.Require(l.Context, name)
+}
+
+// Resource returns the resource with the given name. Prefer ResourceOf for a typed access.
+func (l *Context[Req]) Resource(name string) (any, error) {
+	r, err := resources.Get(l.Context, name)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
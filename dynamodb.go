@@ -0,0 +1,172 @@
+package lambda
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+
+	"github.com/jamillosantos/lambda/internal/resources"
+)
+
+// Names of the events of a DynamoDB stream record.
+const (
+	DynamoDBInsert = "INSERT"
+	DynamoDBModify = "MODIFY"
+	DynamoDBRemove = "REMOVE"
+)
+
+// DynamoDBChange is the request of the handlers of StartDynamoDBStream: the stream record and its images decoded into
+// Item. An image is nil when it is not in the record, which depends on the event and on the StreamViewType of the
+// table (Eg: OldImage is nil for INSERT and NewImage is nil for REMOVE).
+type DynamoDBChange[Item any] struct {
+	Record   events.DynamoDBEventRecord
+	NewImage *Item
+	OldImage *Item
+}
+
+// EventName returns the event of the record: DynamoDBInsert, DynamoDBModify or DynamoDBRemove.
+func (c DynamoDBChange[Item]) EventName() string {
+	return c.Record.EventName
+}
+
+// SequenceNumber returns the sequence number of the record in the stream.
+func (c DynamoDBChange[Item]) SequenceNumber() string {
+	return c.Record.Change.SequenceNumber
+}
+
+// Keys returns the primary key attributes of the changed item.
+func (c DynamoDBChange[Item]) Keys() map[string]events.DynamoDBAttributeValue {
+	return c.Record.Change.Keys
+}
+
+// DynamoDBStreamHandlers are the handlers of StartDynamoDBStream, one per event. The records of the events without
+// handler are ignored.
+type DynamoDBStreamHandlers[Item any] struct {
+	Insert Handler[DynamoDBChange[Item], None]
+	Modify Handler[DynamoDBChange[Item], None]
+	Remove Handler[DynamoDBChange[Item], None]
+}
+
+func (h *DynamoDBStreamHandlers[Item]) handler(eventName string) Handler[DynamoDBChange[Item], None] {
+	switch eventName {
+	case DynamoDBInsert:
+		return h.Insert
+	case DynamoDBModify:
+		return h.Modify
+	case DynamoDBRemove:
+		return h.Remove
+	}
+	return nil
+}
+
+// StartDynamoDBStream will start the lambda function, as the consumer of a DynamoDB stream, with the given handlers and
+// options.
+//
+// The images of each record are decoded into Item with UnmarshalDynamoDBImage, so Item uses `dynamodbav` struct tags
+// as the AWS SDK does, and the record is dispatched to the handler of its event. The records that fail to be decoded
+// or processed are reported as batch item failures by their sequence number: the event source mapping must have
+// ReportBatchItemFailures enabled.
+//
+// Records are processed one at a time, unless WithConcurrency is set. Records of the same item are always processed in
+// order and, once one fails, the next ones of that item are reported as failures without being processed.
+func StartDynamoDBStream[Item any](handlers DynamoDBStreamHandlers[Item], opts ...Option[events.DynamoDBEventResponse]) {
+	c := defaultOpts[events.DynamoDBEventResponse]()
+	for _, o := range opts {
+		o(&c)
+	}
+
+	manager := startManager(&c)
+
+	lambda.StartWithOptions(dynamoDBStreamHandler(handlers, &c, manager), manager.LambdaOptions()...)
+}
+
+func dynamoDBStreamHandler[Item any](handlers DynamoDBStreamHandlers[Item], c *options[events.DynamoDBEventResponse], manager *resources.Manager) func(context.Context, events.DynamoDBEvent) (events.DynamoDBEventResponse, error) {
+	return func(ctx context.Context, event events.DynamoDBEvent) (events.DynamoDBEventResponse, error) {
+		if err := manager.CheckReady(); err != nil {
+			return events.DynamoDBEventResponse{}, err
+		}
+		ctx = resources.NewContext(ctx, manager)
+
+		groups := groupBy(len(event.Records), func(i int) string {
+			return itemKey(event.Records[i].Change.Keys)
+		})
+		failures := processBatch(c.concurrency, groups, func(i int) error {
+			record := event.Records[i]
+			handler := handlers.handler(record.EventName)
+			if handler == nil {
+				return nil
+			}
+			change, err := newDynamoDBChange[Item](record)
+			if err != nil {
+				return err
+			}
+			_, err = handler(&Context[DynamoDBChange[Item]]{
+				Context: ctx,
+				Request: change,
+				Locals:  make(map[string]any),
+			})
+			return err
+		})
+
+		resp := events.DynamoDBEventResponse{
+			BatchItemFailures: make([]events.DynamoDBBatchItemFailure, 0, len(failures)),
+		}
+		for i, record := range event.Records {
+			if err, ok := failures[i]; ok {
+				log.Printf("failed to process DynamoDB stream record %s: %v", record.Change.SequenceNumber, err)
+				resp.BatchItemFailures = append(resp.BatchItemFailures, events.DynamoDBBatchItemFailure{ItemIdentifier: record.Change.SequenceNumber})
+			}
+		}
+		return resp, nil
+	}
+}
+
+func newDynamoDBChange[Item any](record events.DynamoDBEventRecord) (DynamoDBChange[Item], error) {
+	change := DynamoDBChange[Item]{Record: record}
+	if len(record.Change.NewImage) > 0 {
+		change.NewImage = new(Item)
+		if err := UnmarshalDynamoDBImage(record.Change.NewImage, change.NewImage); err != nil {
+			return change, fmt.Errorf("failed to decode the new image: %w", err)
+		}
+	}
+	if len(record.Change.OldImage) > 0 {
+		change.OldImage = new(Item)
+		if err := UnmarshalDynamoDBImage(record.Change.OldImage, change.OldImage); err != nil {
+			return change, fmt.Errorf("failed to decode the old image: %w", err)
+		}
+	}
+	return change, nil
+}
+
+// itemKey returns a string that identifies the item with the given primary key attributes.
+func itemKey(keys map[string]events.DynamoDBAttributeValue) string {
+	if len(keys) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(keys))
+	for name := range keys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var sb strings.Builder
+	for _, name := range names {
+		key := keys[name]
+		var value string
+		switch key.DataType() {
+		case events.DataTypeString:
+			value = key.String()
+		case events.DataTypeNumber:
+			value = key.Number()
+		case events.DataTypeBinary:
+			value = string(key.Binary())
+		}
+		fmt.Fprintf(&sb, "%s=%d:%q;", name, key.DataType(), value)
+	}
+	return sb.String()
+}
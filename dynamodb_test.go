@@ -0,0 +1,113 @@
+package lambda
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jamillosantos/lambda/internal/resources"
+)
+
+type streamItem struct {
+	ID     string `dynamodbav:"id"`
+	Status string `dynamodbav:"status"`
+}
+
+func newStreamRecord(eventName, sequence, id string, newImage, oldImage map[string]events.DynamoDBAttributeValue) events.DynamoDBEventRecord {
+	return events.DynamoDBEventRecord{
+		EventName: eventName,
+		Change: events.DynamoDBStreamRecord{
+			SequenceNumber: sequence,
+			Keys:           map[string]events.DynamoDBAttributeValue{"id": events.NewStringAttribute(id)},
+			NewImage:       newImage,
+			OldImage:       oldImage,
+		},
+	}
+}
+
+func streamImage(id, status string) map[string]events.DynamoDBAttributeValue {
+	return map[string]events.DynamoDBAttributeValue{
+		"id":     events.NewStringAttribute(id),
+		"status": events.NewStringAttribute(status),
+	}
+}
+
+func TestStartDynamoDBStream(t *testing.T) {
+	t.Run("should dispatch the records to the handler of their event", func(t *testing.T) {
+		var calls []string
+		record := func(name string) Handler[DynamoDBChange[streamItem], None] {
+			return func(ctx *Context[DynamoDBChange[streamItem]]) (None, error) {
+				change := ctx.Request
+				call := name + ":" + change.SequenceNumber()
+				if change.OldImage != nil {
+					call += ":" + change.OldImage.Status
+				}
+				if change.NewImage != nil {
+					call += ":" + change.NewImage.Status
+				}
+				calls = append(calls, call)
+				return None{}, nil
+			}
+		}
+		c := defaultOpts[events.DynamoDBEventResponse]()
+		h := dynamoDBStreamHandler(DynamoDBStreamHandlers[streamItem]{
+			Insert: record("insert"),
+			Modify: record("modify"),
+			Remove: record("remove"),
+		}, &c, resources.NewStartedManager(nil))
+
+		resp, err := h(context.Background(), events.DynamoDBEvent{Records: []events.DynamoDBEventRecord{
+			newStreamRecord(DynamoDBInsert, "1", "a", streamImage("a", "new"), nil),
+			newStreamRecord(DynamoDBModify, "2", "a", streamImage("a", "paid"), streamImage("a", "new")),
+			newStreamRecord(DynamoDBRemove, "3", "a", nil, streamImage("a", "paid")),
+		}})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"insert:1:new", "modify:2:new:paid", "remove:3:paid"}, calls)
+		assert.Empty(t, resp.BatchItemFailures)
+	})
+
+	t.Run("should ignore the events without handler", func(t *testing.T) {
+		c := defaultOpts[events.DynamoDBEventResponse]()
+		h := dynamoDBStreamHandler(DynamoDBStreamHandlers[streamItem]{}, &c, resources.NewStartedManager(nil))
+
+		resp, err := h(context.Background(), events.DynamoDBEvent{Records: []events.DynamoDBEventRecord{
+			newStreamRecord(DynamoDBInsert, "1", "a", streamImage("a", "new"), nil),
+		}})
+		require.NoError(t, err)
+		assert.Empty(t, resp.BatchItemFailures)
+	})
+
+	t.Run("should report the failed records by sequence number and skip the next changes of the item", func(t *testing.T) {
+		var processed []string
+		c := defaultOpts[events.DynamoDBEventResponse]()
+		h := dynamoDBStreamHandler(DynamoDBStreamHandlers[streamItem]{
+			Insert: func(ctx *Context[DynamoDBChange[streamItem]]) (None, error) {
+				processed = append(processed, ctx.Request.SequenceNumber())
+				if ctx.Request.NewImage.ID == "a" {
+					return None{}, errors.New("failure")
+				}
+				return None{}, nil
+			},
+			Modify: func(ctx *Context[DynamoDBChange[streamItem]]) (None, error) {
+				processed = append(processed, ctx.Request.SequenceNumber())
+				return None{}, nil
+			},
+		}, &c, resources.NewStartedManager(nil))
+
+		resp, err := h(context.Background(), events.DynamoDBEvent{Records: []events.DynamoDBEventRecord{
+			newStreamRecord(DynamoDBInsert, "1", "a", streamImage("a", "new"), nil),
+			newStreamRecord(DynamoDBInsert, "2", "b", streamImage("b", "new"), nil),
+			newStreamRecord(DynamoDBModify, "3", "a", streamImage("a", "paid"), nil),
+			newStreamRecord(DynamoDBModify, "4", "b", map[string]events.DynamoDBAttributeValue{"status": events.NewBooleanAttribute(true)}, nil),
+		}})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"1", "2"}, processed)
+		assert.Equal(t, []events.DynamoDBBatchItemFailure{
+			{ItemIdentifier: "1"}, {ItemIdentifier: "3"}, {ItemIdentifier: "4"},
+		}, resp.BatchItemFailures)
+	})
+}
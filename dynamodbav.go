@@ -0,0 +1,268 @@
+package lambda
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+var (
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+	timeType            = reflect.TypeFor[time.Time]()
+)
+
+// UnmarshalDynamoDBImage decodes an item image of a DynamoDB stream record into v, a pointer to a struct or to a map
+// with string keys.
+//
+// Struct fields are matched by the `dynamodbav` tag, as the attributevalue package of the AWS SDK does, falling back
+// to the field name. Fields tagged with "-" are ignored and embedded structs are flattened. Numbers are decoded into
+// any numeric type or a string, lists and sets into slices, maps into structs or maps, and strings into strings,
+// time.Time (RFC 3339) or encoding.TextUnmarshaler. A time.Time can also be decoded from a number of seconds since
+// the Unix epoch.
+func UnmarshalDynamoDBImage(image map[string]events.DynamoDBAttributeValue, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("unmarshal dynamodb image: expected a pointer, got %T", v)
+	}
+	return unmarshalAttributeMap(image, rv.Elem())
+}
+
+func unmarshalAttributeMap(m map[string]events.DynamoDBAttributeValue, rv reflect.Value) error {
+	switch rv.Kind() {
+	case reflect.Struct:
+		return unmarshalStruct(m, rv)
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return fmt.Errorf("cannot decode a map into %s", rv.Type())
+		}
+		if rv.IsNil() {
+			rv.Set(reflect.MakeMapWithSize(rv.Type(), len(m)))
+		}
+		for k, av := range m {
+			elem := reflect.New(rv.Type().Elem()).Elem()
+			if err := unmarshalAttribute(av, elem); err != nil {
+				return fmt.Errorf("%s: %w", k, err)
+			}
+			rv.SetMapIndex(reflect.ValueOf(k).Convert(rv.Type().Key()), elem)
+		}
+		return nil
+	case reflect.Interface:
+		if rv.NumMethod() == 0 {
+			rv.Set(reflect.ValueOf(attributeMapValue(m)))
+			return nil
+		}
+	}
+	return fmt.Errorf("cannot decode a map into %s", rv.Type())
+}
+
+func unmarshalStruct(m map[string]events.DynamoDBAttributeValue, rv reflect.Value) error {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		f := rt.Field(i)
+		tag := f.Tag.Get("dynamodbav")
+		name, _, _ := strings.Cut(tag, ",")
+		if name == "-" {
+			continue
+		}
+		if f.Anonymous && f.Type.Kind() == reflect.Struct && name == "" {
+			if err := unmarshalStruct(m, rv.Field(i)); err != nil {
+				return err
+			}
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		av, ok := m[name]
+		if !ok {
+			continue
+		}
+		if err := unmarshalAttribute(av, rv.Field(i)); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func unmarshalAttribute(av events.DynamoDBAttributeValue, rv reflect.Value) error {
+	if av.IsNull() {
+		rv.Set(reflect.Zero(rv.Type()))
+		return nil
+	}
+	if rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			rv.Set(reflect.New(rv.Type().Elem()))
+		}
+		return unmarshalAttribute(av, rv.Elem())
+	}
+	if rv.Kind() == reflect.Interface && rv.NumMethod() == 0 {
+		rv.Set(reflect.ValueOf(attributeValue(av)))
+		return nil
+	}
+
+	switch av.DataType() {
+	case events.DataTypeString:
+		return unmarshalString(av.String(), rv)
+	case events.DataTypeNumber:
+		return unmarshalNumber(av.Number(), rv)
+	case events.DataTypeBoolean:
+		if rv.Kind() != reflect.Bool {
+			return fmt.Errorf("cannot decode a boolean into %s", rv.Type())
+		}
+		rv.SetBool(av.Boolean())
+		return nil
+	case events.DataTypeBinary:
+		if rv.Kind() != reflect.Slice || rv.Type().Elem().Kind() != reflect.Uint8 {
+			return fmt.Errorf("cannot decode binary into %s", rv.Type())
+		}
+		rv.SetBytes(append([]byte(nil), av.Binary()...))
+		return nil
+	case events.DataTypeMap:
+		return unmarshalAttributeMap(av.Map(), rv)
+	case events.DataTypeList:
+		return unmarshalList(av.List(), rv)
+	case events.DataTypeStringSet:
+		return unmarshalList(toAttributes(av.StringSet(), events.NewStringAttribute), rv)
+	case events.DataTypeNumberSet:
+		return unmarshalList(toAttributes(av.NumberSet(), events.NewNumberAttribute), rv)
+	case events.DataTypeBinarySet:
+		return unmarshalList(toAttributes(av.BinarySet(), events.NewBinaryAttribute), rv)
+	}
+	return fmt.Errorf("unsupported attribute type %d", av.DataType())
+}
+
+func unmarshalString(s string, rv reflect.Value) error {
+	if rv.Type() == timeType {
+		t, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			return err
+		}
+		rv.Set(reflect.ValueOf(t))
+		return nil
+	}
+	if rv.CanAddr() && reflect.PointerTo(rv.Type()).Implements(textUnmarshalerType) {
+		return rv.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+	}
+	if rv.Kind() != reflect.String {
+		return fmt.Errorf("cannot decode a string into %s", rv.Type())
+	}
+	rv.SetString(s)
+	return nil
+}
+
+func unmarshalNumber(n string, rv reflect.Value) error {
+	if rv.Type() == timeType {
+		seconds, err := strconv.ParseInt(n, 10, 64)
+		if err != nil {
+			return err
+		}
+		rv.Set(reflect.ValueOf(time.Unix(seconds, 0).UTC()))
+		return nil
+	}
+	switch rv.Kind() {
+	case reflect.String:
+		rv.SetString(n)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(n, 10, rv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		rv.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(n, 10, rv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		rv.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(n, rv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		rv.SetFloat(f)
+	default:
+		return fmt.Errorf("cannot decode a number into %s", rv.Type())
+	}
+	return nil
+}
+
+func unmarshalList(list []events.DynamoDBAttributeValue, rv reflect.Value) error {
+	switch rv.Kind() {
+	case reflect.Slice:
+		slice := reflect.MakeSlice(rv.Type(), len(list), len(list))
+		for i, av := range list {
+			if err := unmarshalAttribute(av, slice.Index(i)); err != nil {
+				return fmt.Errorf("[%d]: %w", i, err)
+			}
+		}
+		rv.Set(slice)
+		return nil
+	case reflect.Array:
+		if len(list) > rv.Len() {
+			return fmt.Errorf("cannot decode %d elements into %s", len(list), rv.Type())
+		}
+		for i, av := range list {
+			if err := unmarshalAttribute(av, rv.Index(i)); err != nil {
+				return fmt.Errorf("[%d]: %w", i, err)
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("cannot decode a list into %s", rv.Type())
+}
+
+func toAttributes[T any](values []T, newAttribute func(T) events.DynamoDBAttributeValue) []events.DynamoDBAttributeValue {
+	list := make([]events.DynamoDBAttributeValue, len(values))
+	for i, v := range values {
+		list[i] = newAttribute(v)
+	}
+	return list
+}
+
+// attributeValue converts the attribute into a plain Go value, as decoded into an empty interface.
+func attributeValue(av events.DynamoDBAttributeValue) any {
+	switch av.DataType() {
+	case events.DataTypeString:
+		return av.String()
+	case events.DataTypeNumber:
+		if f, err := av.Float(); err == nil {
+			return f
+		}
+		return av.Number()
+	case events.DataTypeBoolean:
+		return av.Boolean()
+	case events.DataTypeBinary:
+		return av.Binary()
+	case events.DataTypeMap:
+		return attributeMapValue(av.Map())
+	case events.DataTypeList:
+		list := make([]any, len(av.List()))
+		for i, item := range av.List() {
+			list[i] = attributeValue(item)
+		}
+		return list
+	case events.DataTypeStringSet:
+		return av.StringSet()
+	case events.DataTypeNumberSet:
+		return av.NumberSet()
+	case events.DataTypeBinarySet:
+		return av.BinarySet()
+	}
+	return nil
+}
+
+func attributeMapValue(m map[string]events.DynamoDBAttributeValue) map[string]any {
+	r := make(map[string]any, len(m))
+	for k, av := range m {
+		r[k] = attributeValue(av)
+	}
+	return r
+}
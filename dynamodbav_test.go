@@ -0,0 +1,110 @@
+package lambda
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type avAddress struct {
+	City string `dynamodbav:"city"`
+}
+
+type avBase struct {
+	PK string `dynamodbav:"pk"`
+}
+
+type avItem struct {
+	avBase
+	Name      string            `dynamodbav:"name"`
+	Age       int               `dynamodbav:"age,omitempty"`
+	Score     float64           `dynamodbav:"score"`
+	Active    bool              `dynamodbav:"active"`
+	Data      []byte            `dynamodbav:"data"`
+	Tags      []string          `dynamodbav:"tags"`
+	Sizes     []int             `dynamodbav:"sizes"`
+	Address   *avAddress        `dynamodbav:"address"`
+	Labels    map[string]string `dynamodbav:"labels"`
+	CreatedAt time.Time         `dynamodbav:"created_at"`
+	ExpiresAt time.Time         `dynamodbav:"expires_at"`
+	Extra     any               `dynamodbav:"extra"`
+	Nickname  *string           `dynamodbav:"nickname"`
+	Ignored   string            `dynamodbav:"-"`
+	Untagged  string
+}
+
+func TestUnmarshalDynamoDBImage(t *testing.T) {
+	t.Run("should decode the attributes into the tagged fields", func(t *testing.T) {
+		image := map[string]events.DynamoDBAttributeValue{
+			"pk":         events.NewStringAttribute("user#1"),
+			"name":       events.NewStringAttribute("John"),
+			"age":        events.NewNumberAttribute("42"),
+			"score":      events.NewNumberAttribute("9.5"),
+			"active":     events.NewBooleanAttribute(true),
+			"data":       events.NewBinaryAttribute([]byte("raw")),
+			"tags":       events.NewStringSetAttribute([]string{"a", "b"}),
+			"sizes":      events.NewListAttribute([]events.DynamoDBAttributeValue{events.NewNumberAttribute("1"), events.NewNumberAttribute("2")}),
+			"address":    events.NewMapAttribute(map[string]events.DynamoDBAttributeValue{"city": events.NewStringAttribute("Recife")}),
+			"labels":     events.NewMapAttribute(map[string]events.DynamoDBAttributeValue{"env": events.NewStringAttribute("prod")}),
+			"created_at": events.NewStringAttribute("2024-01-02T03:04:05Z"),
+			"expires_at": events.NewNumberAttribute("1704164645"),
+			"extra":      events.NewListAttribute([]events.DynamoDBAttributeValue{events.NewStringAttribute("x"), events.NewNumberAttribute("1")}),
+			"nickname":   events.NewNullAttribute(),
+			"Ignored":    events.NewStringAttribute("ignored"),
+			"Untagged":   events.NewStringAttribute("untagged"),
+		}
+
+		var item avItem
+		require.NoError(t, UnmarshalDynamoDBImage(image, &item))
+		created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+		assert.Equal(t, avItem{
+			avBase:    avBase{PK: "user#1"},
+			Name:      "John",
+			Age:       42,
+			Score:     9.5,
+			Active:    true,
+			Data:      []byte("raw"),
+			Tags:      []string{"a", "b"},
+			Sizes:     []int{1, 2},
+			Address:   &avAddress{City: "Recife"},
+			Labels:    map[string]string{"env": "prod"},
+			CreatedAt: created,
+			ExpiresAt: created,
+			Extra:     []any{"x", float64(1)},
+			Untagged:  "untagged",
+		}, item)
+	})
+
+	t.Run("should decode into a map", func(t *testing.T) {
+		var item map[string]any
+		require.NoError(t, UnmarshalDynamoDBImage(map[string]events.DynamoDBAttributeValue{
+			"name": events.NewStringAttribute("John"),
+			"age":  events.NewNumberAttribute("42"),
+		}, &item))
+		assert.Equal(t, map[string]any{"name": "John", "age": float64(42)}, item)
+	})
+
+	t.Run("should fail with the path of the attribute of a mismatched type", func(t *testing.T) {
+		var item avItem
+		err := UnmarshalDynamoDBImage(map[string]events.DynamoDBAttributeValue{
+			"address": events.NewMapAttribute(map[string]events.DynamoDBAttributeValue{"city": events.NewBooleanAttribute(true)}),
+		}, &item)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "address: city: cannot decode a boolean into string")
+	})
+
+	t.Run("should fail when the number overflows the field", func(t *testing.T) {
+		var item struct {
+			N int8 `dynamodbav:"n"`
+		}
+		err := UnmarshalDynamoDBImage(map[string]events.DynamoDBAttributeValue{"n": events.NewNumberAttribute("300")}, &item)
+		assert.Error(t, err)
+	})
+
+	t.Run("should fail when the target is not a pointer", func(t *testing.T) {
+		assert.Error(t, UnmarshalDynamoDBImage(nil, avItem{}))
+	})
+}
@@ -0,0 +1,152 @@
+package lambda
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+
+	"github.com/jamillosantos/lambda/internal/resources"
+)
+
+// ErrUnmatchedEvent is returned when no route matches the source and detail-type of an EventBridge event, unless the
+// router ignores unmatched events.
+var ErrUnmatchedEvent = errors.New("no route for the EventBridge event")
+
+// EventBridgeMessage is the request of the handlers registered with HandleEvent: the event envelope (ID, Time,
+// Region, Resources...) and its detail decoded into Detail. The raw detail is still available in
+// EventBridgeEvent.Detail.
+type EventBridgeMessage[Detail any] struct {
+	events.EventBridgeEvent
+	Detail Detail
+}
+
+// EventDetailDecodeError is returned when the detail of an EventBridge event cannot be decoded into the type of its
+// route.
+type EventDetailDecodeError struct {
+	Source     string
+	DetailType string
+	Err        error
+}
+
+func (e *EventDetailDecodeError) Error() string {
+	return fmt.Sprintf("malformed detail for %s/%s: %v", e.Source, e.DetailType, e.Err)
+}
+
+func (e *EventDetailDecodeError) Unwrap() error {
+	return e.Err
+}
+
+type eventRoute struct {
+	source     string
+	detailType string
+}
+
+// EventBridgeRouter dispatches EventBridge events to handlers registered by source and detail-type.
+//
+//	r := lambda.NewEventBridgeRouter()
+//	lambda.HandleEvent(r, "com.acme.orders", "OrderPlaced", onOrderPlaced)
+//	lambda.StartEventBridge(r)
+type EventBridgeRouter struct {
+	routes          map[eventRoute]Handler[events.EventBridgeEvent, None]
+	middlewares     []Middleware[events.EventBridgeEvent, None]
+	ignoreUnmatched bool
+}
+
+// NewEventBridgeRouter creates an empty EventBridgeRouter.
+func NewEventBridgeRouter() *EventBridgeRouter {
+	return &EventBridgeRouter{
+		routes: make(map[eventRoute]Handler[events.EventBridgeEvent, None]),
+	}
+}
+
+// Use adds middlewares wrapping every route. They also wrap unmatched events.
+func (r *EventBridgeRouter) Use(middlewares ...Middleware[events.EventBridgeEvent, None]) *EventBridgeRouter {
+	r.middlewares = append(r.middlewares, middlewares...)
+	return r
+}
+
+// IgnoreUnmatched makes the router acknowledge the events no route matches, instead of failing with
+// ErrUnmatchedEvent.
+func (r *EventBridgeRouter) IgnoreUnmatched() *EventBridgeRouter {
+	r.ignoreUnmatched = true
+	return r
+}
+
+// Handle registers a handler for the events with the given source and detail-type. An empty source or detail-type
+// matches any value. When several routes match an event, the exact match is preferred, then the detail-type match and
+// then the source match.
+func (r *EventBridgeRouter) Handle(source, detailType string, handler Handler[events.EventBridgeEvent, None], middlewares ...Middleware[events.EventBridgeEvent, None]) *EventBridgeRouter {
+	if len(middlewares) > 0 {
+		handler = Use(handler, middlewares...)
+	}
+	r.routes[eventRoute{source: source, detailType: detailType}] = handler
+	return r
+}
+
+// HandleEvent registers, on the router, a handler receiving the detail of the event decoded as JSON into Detail. A
+// detail that cannot be decoded results in an EventDetailDecodeError.
+func HandleEvent[Detail any](r *EventBridgeRouter, source, detailType string, handler Handler[EventBridgeMessage[Detail], None], middlewares ...Middleware[events.EventBridgeEvent, None]) *EventBridgeRouter {
+	return r.Handle(source, detailType, func(ctx *Context[events.EventBridgeEvent]) (None, error) {
+		msg := EventBridgeMessage[Detail]{EventBridgeEvent: ctx.Request}
+		if len(ctx.Request.Detail) > 0 {
+			if err := json.Unmarshal(ctx.Request.Detail, &msg.Detail); err != nil {
+				return None{}, &EventDetailDecodeError{Source: ctx.Request.Source, DetailType: ctx.Request.DetailType, Err: err}
+			}
+		}
+		return handler(&Context[EventBridgeMessage[Detail]]{
+			Context: ctx.Context,
+			Request: msg,
+			Locals:  ctx.Locals,
+		})
+	}, middlewares...)
+}
+
+// Serve dispatches the event to the route matching its source and detail-type. It is a Handler, so it can be used
+// with Start as well.
+func (r *EventBridgeRouter) Serve(ctx *Context[events.EventBridgeEvent]) (None, error) {
+	return Use(r.dispatch, r.middlewares...)(ctx)
+}
+
+func (r *EventBridgeRouter) dispatch(ctx *Context[events.EventBridgeEvent]) (None, error) {
+	source, detailType := ctx.Request.Source, ctx.Request.DetailType
+	for _, route := range []eventRoute{{source, detailType}, {"", detailType}, {source, ""}, {"", ""}} {
+		if handler, ok := r.routes[route]; ok {
+			return handler(ctx)
+		}
+	}
+	if r.ignoreUnmatched {
+		return None{}, nil
+	}
+	return None{}, fmt.Errorf("%w: %s/%s", ErrUnmatchedEvent, source, detailType)
+}
+
+// StartEventBridge will start the lambda function, as the target of EventBridge rules, with the given router and
+// options.
+func StartEventBridge(router *EventBridgeRouter, opts ...Option[None]) {
+	c := defaultOpts[None]()
+	for _, o := range opts {
+		o(&c)
+	}
+
+	manager := startManager(&c)
+
+	lambda.StartWithOptions(eventBridgeHandler(router, manager), manager.LambdaOptions()...)
+}
+
+func eventBridgeHandler(router *EventBridgeRouter, manager *resources.Manager) func(context.Context, events.EventBridgeEvent) error {
+	return func(ctx context.Context, event events.EventBridgeEvent) error {
+		if err := manager.CheckReady(); err != nil {
+			return err
+		}
+		_, err := router.Serve(&Context[events.EventBridgeEvent]{
+			Context: resources.NewContext(ctx, manager),
+			Request: event,
+			Locals:  make(map[string]any),
+		})
+		return err
+	}
+}
@@ -0,0 +1,85 @@
+package lambda
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jamillosantos/lambda/internal/resources"
+)
+
+type orderPlaced struct {
+	OrderID string `json:"orderId"`
+}
+
+func newEventBridgeEvent(source, detailType, detail string) events.EventBridgeEvent {
+	return events.EventBridgeEvent{
+		ID:         "event-1",
+		Source:     source,
+		DetailType: detailType,
+		Region:     "us-east-1",
+		Time:       time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Resources:  []string{"arn:aws:events:us-east-1:123456789012:rule/orders"},
+		Detail:     json.RawMessage(detail),
+	}
+}
+
+func TestEventBridgeRouter(t *testing.T) {
+	var (
+		got     []EventBridgeMessage[orderPlaced]
+		matched []string
+	)
+	r := NewEventBridgeRouter()
+	HandleEvent(r, "com.acme.orders", "OrderPlaced", func(ctx *Context[EventBridgeMessage[orderPlaced]]) (None, error) {
+		got = append(got, ctx.Request)
+		return None{}, nil
+	})
+	r.Handle("", "OrderCancelled", func(ctx *Context[events.EventBridgeEvent]) (None, error) {
+		matched = append(matched, "detail-type")
+		return None{}, nil
+	})
+	r.Handle("com.acme.billing", "", func(ctx *Context[events.EventBridgeEvent]) (None, error) {
+		matched = append(matched, "source")
+		return None{}, nil
+	})
+	h := eventBridgeHandler(r, resources.NewStartedManager(nil))
+
+	t.Run("should decode the detail and expose the envelope", func(t *testing.T) {
+		require.NoError(t, h(context.Background(), newEventBridgeEvent("com.acme.orders", "OrderPlaced", `{"orderId":"42"}`)))
+		require.Len(t, got, 1)
+		assert.Equal(t, orderPlaced{OrderID: "42"}, got[0].Detail)
+		assert.Equal(t, "event-1", got[0].ID)
+		assert.Equal(t, "us-east-1", got[0].Region)
+		assert.Equal(t, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), got[0].Time)
+		assert.Equal(t, []string{"arn:aws:events:us-east-1:123456789012:rule/orders"}, got[0].Resources)
+	})
+
+	t.Run("should prefer the detail-type over the source", func(t *testing.T) {
+		matched = nil
+		require.NoError(t, h(context.Background(), newEventBridgeEvent("com.acme.billing", "OrderCancelled", `{}`)))
+		require.NoError(t, h(context.Background(), newEventBridgeEvent("com.acme.billing", "InvoicePaid", `{}`)))
+		assert.Equal(t, []string{"detail-type", "source"}, matched)
+	})
+
+	t.Run("should fail with EventDetailDecodeError for malformed details", func(t *testing.T) {
+		err := h(context.Background(), newEventBridgeEvent("com.acme.orders", "OrderPlaced", `{"orderId":42}`))
+		var decodeErr *EventDetailDecodeError
+		require.ErrorAs(t, err, &decodeErr)
+		assert.Equal(t, "OrderPlaced", decodeErr.DetailType)
+	})
+
+	t.Run("should reject unmatched events", func(t *testing.T) {
+		err := h(context.Background(), newEventBridgeEvent("com.acme.users", "UserCreated", `{}`))
+		require.ErrorIs(t, err, ErrUnmatchedEvent)
+	})
+
+	t.Run("should ignore unmatched events when configured", func(t *testing.T) {
+		h := eventBridgeHandler(NewEventBridgeRouter().IgnoreUnmatched(), resources.NewStartedManager(nil))
+		require.NoError(t, h(context.Background(), newEventBridgeEvent("com.acme.users", "UserCreated", `{}`)))
+	})
+}
@@ -0,0 +1,89 @@
+package http
+
+import (
+	"encoding/base64"
+	"io"
+	"mime"
+	"strings"
+)
+
+// Bytes writes binary data (Eg: an image or a PDF) with the given Content-Type. The body is sent to API Gateway
+// base64 encoded, whatever the Content-Type.
+func (r *Response[T]) Bytes(contentType string, data []byte) *Response[T] {
+	r.Raw(contentType, data)
+	r.binary = true
+	return r
+}
+
+// Stream sets a reader as the body, with the given Content-Type. The reader is consumed when the response is sent to
+// API Gateway, base64 encoded. If the reader implements io.Closer, it is closed after being consumed.
+func (r *Response[T]) Stream(contentType string, reader io.Reader) *Response[T] {
+	r.resetBody()
+	r.setHeader("Content-Type", contentType)
+	r.stream = reader
+	r.binary = true
+	return r
+}
+
+// IsBinary reports whether the body is sent base64 encoded. That is the case when it was set by Bytes or Stream, when
+// it has a Content-Encoding (Eg: gzip) or when its Content-Type is not textual.
+func (r *Response[T]) IsBinary() bool {
+	if r.binary {
+		return true
+	}
+	if headerValue(r.Headers, "Content-Encoding") != "" {
+		return true
+	}
+	return !isTextual(headerValue(r.Headers, "Content-Type"))
+}
+
+// ReadBody returns the body of the response, consuming the reader set by Stream, if any.
+func (r *Response[T]) ReadBody() ([]byte, error) {
+	if r.stream != nil {
+		stream := r.stream
+		r.stream = nil
+		if closer, ok := stream.(io.Closer); ok {
+			defer closer.Close()
+		}
+		if _, err := r.Body.ReadFrom(stream); err != nil {
+			return nil, err
+		}
+	}
+	return r.Body.Bytes(), nil
+}
+
+// GatewayBody returns the body as sent to API Gateway: base64 encoded when IsBinary, as is otherwise.
+func (r *Response[T]) GatewayBody() (body string, isBase64Encoded bool, err error) {
+	b, err := r.ReadBody()
+	if err != nil {
+		return "", false, err
+	}
+	if r.IsBinary() {
+		return base64.StdEncoding.EncodeToString(b), true, nil
+	}
+	return string(b), false, nil
+}
+
+// isTextual reports whether the content type is text that can be sent to API Gateway as is. Responses without
+// Content-Type are considered textual.
+func isTextual(contentType string) bool {
+	if contentType == "" {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	typ, subtype, _ := strings.Cut(mediaType, "/")
+	if typ == "text" {
+		return true
+	}
+	if typ != "application" && mediaType != "image/svg+xml" {
+		return false
+	}
+	switch subtype {
+	case "json", "xml", "javascript", "x-www-form-urlencoded", "graphql":
+		return true
+	}
+	return strings.HasSuffix(subtype, "+json") || strings.HasSuffix(subtype, "+xml")
+}
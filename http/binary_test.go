@@ -0,0 +1,111 @@
+package http
+
+import (
+	"encoding/base64"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type closeRecorder struct {
+	io.Reader
+	closed bool
+}
+
+func (c *closeRecorder) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestResponse_Bytes(t *testing.T) {
+	t.Run("should send the body base64 encoded", func(t *testing.T) {
+		r := NewResponse[None]("", nil)
+		r.Bytes("application/pdf", []byte{0x25, 0x50, 0x44, 0x46, 0x00})
+
+		body, isBase64Encoded, err := r.GatewayBody()
+		require.NoError(t, err)
+		assert.True(t, isBase64Encoded)
+		assert.Equal(t, base64.StdEncoding.EncodeToString([]byte{0x25, 0x50, 0x44, 0x46, 0x00}), body)
+		assert.Equal(t, "application/pdf", r.Headers["Content-Type"])
+	})
+
+	t.Run("should encode textual content types when set explicitly as binary", func(t *testing.T) {
+		r := NewResponse[None]("", nil)
+		r.Bytes("text/plain", []byte("hello"))
+
+		_, isBase64Encoded, err := r.GatewayBody()
+		require.NoError(t, err)
+		assert.True(t, isBase64Encoded)
+	})
+
+	t.Run("should not encode the body when replaced by a textual one", func(t *testing.T) {
+		r := NewResponse[None]("", nil)
+		r.Bytes("image/png", []byte{0x89})
+		r.JSON(map[string]string{"ok": "true"})
+
+		body, isBase64Encoded, err := r.GatewayBody()
+		require.NoError(t, err)
+		assert.False(t, isBase64Encoded)
+		assert.JSONEq(t, `{"ok":"true"}`, body)
+	})
+}
+
+func TestResponse_Stream(t *testing.T) {
+	t.Run("should consume and close the reader", func(t *testing.T) {
+		reader := &closeRecorder{Reader: strings.NewReader("file content")}
+		r := NewResponse[None]("", nil)
+		r.Stream("application/octet-stream", reader)
+
+		body, isBase64Encoded, err := r.GatewayBody()
+		require.NoError(t, err)
+		assert.True(t, isBase64Encoded)
+		assert.Equal(t, base64.StdEncoding.EncodeToString([]byte("file content")), body)
+		assert.True(t, reader.closed)
+	})
+
+	t.Run("should return the content of the stream from ReadBody", func(t *testing.T) {
+		r := NewResponse[None]("", nil)
+		r.Stream("application/octet-stream", strings.NewReader("abc"))
+
+		b, err := r.ReadBody()
+		require.NoError(t, err)
+		assert.Equal(t, []byte("abc"), b)
+	})
+}
+
+func TestResponse_IsBinary(t *testing.T) {
+	t.Run("should consider a body with Content-Encoding binary", func(t *testing.T) {
+		r := NewResponse[None]("", nil)
+		r.Raw("application/json", []byte("{}"))
+		r.Headers["Content-Encoding"] = "gzip"
+		assert.True(t, r.IsBinary())
+	})
+
+	t.Run("should not consider a response without Content-Type binary", func(t *testing.T) {
+		assert.False(t, NewResponse[None]("", nil).IsBinary())
+	})
+}
+
+func TestIsTextual(t *testing.T) {
+	tests := map[string]bool{
+		"":                                  true,
+		"text/html; charset=utf-8":          true,
+		"application/json":                  true,
+		"application/problem+json":          true,
+		"application/atom+xml":              true,
+		"application/x-www-form-urlencoded": true,
+		"image/svg+xml":                     true,
+		"image/png":                         false,
+		"application/octet-stream":          false,
+		"application/msgpack":               false,
+		"invalid;;":                         false,
+	}
+	for contentType, want := range tests {
+		t.Run("should classify "+contentType, func(t *testing.T) {
+			assert.Equal(t, want, isTextual(contentType))
+		})
+	}
+}
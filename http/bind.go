@@ -0,0 +1,182 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Binding sources, as set in the `in` of a ParamError.
+const (
+	BindQuery  = "query"
+	BindPath   = "path"
+	BindHeader = "header"
+	BindCookie = "cookie"
+)
+
+var bindSources = []string{BindQuery, BindPath, BindHeader, BindCookie}
+
+// ParamError describes why a parameter of the request could not be bound.
+type ParamError struct {
+	// In is where the parameter comes from: query, path, header or cookie.
+	In      string `json:"in"`
+	Name    string `json:"name"`
+	Message string `json:"message"`
+}
+
+func (e ParamError) Error() string {
+	return e.In + " parameter " + e.Name + ": " + e.Message
+}
+
+// BindError is returned by Request.Bind when parameters cannot be bound. It implements ErrorResponse as a 400 Bad
+// Request listing every invalid parameter.
+type BindError struct {
+	Params []ParamError
+}
+
+func (e *BindError) Error() string {
+	msgs := make([]string, len(e.Params))
+	for i, p := range e.Params {
+		msgs[i] = p.Error()
+	}
+	return "invalid request parameters: " + strings.Join(msgs, "; ")
+}
+
+func (e *BindError) HttpStatusCode() int {
+	return http.StatusBadRequest
+}
+
+func (e *BindError) HttpHeaders() map[string]string {
+	return nil
+}
+
+type bindErrorBody struct {
+	Message string       `json:"message"`
+	Errors  []ParamError `json:"errors"`
+}
+
+func (e *BindError) HttpBody() (json.RawMessage, error) {
+	return json.Marshal(bindErrorBody{
+		Message: "invalid request parameters",
+		Errors:  e.Params,
+	})
+}
+
+// Bind fills the struct pointed by v with the parameters of the request, according to the tags of its fields:
+//
+//	type ListParams struct {
+//		ID      int64         `path:"id"`
+//		Limit   int           `query:"limit" default:"10"`
+//		Tags    []string      `query:"tag"`
+//		Tenant  string        `header:"X-Tenant"`
+//		Session string        `cookie:"session"`
+//		Since   time.Time     `query:"since"`
+//		Timeout time.Duration `header:"X-Timeout" default:"5s"`
+//	}
+//
+// Fields support the same types as the form decoder: strings, booleans, numbers, time.Time (RFC 3339),
+// time.Duration, encoding.TextUnmarshaler, pointers to them and slices of them. Slices receive every value of the
+// query parameter or header, comma-separated values included. The `default` tag is used when the parameter is
+// missing. Embedded structs are bound as well.
+//
+// All the parameters that fail to be parsed are reported at once as a BindError.
+func (r *Request[T]) Bind(v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("bind: expected a pointer to a struct, got %T", v)
+	}
+	var errs []ParamError
+	r.bindStruct(rv.Elem(), &errs)
+	if len(errs) > 0 {
+		return &BindError{Params: errs}
+	}
+	return nil
+}
+
+func (r *Request[T]) bindStruct(rv reflect.Value, errs *[]ParamError) {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		f := rt.Field(i)
+		if f.Anonymous && f.Type.Kind() == reflect.Struct {
+			r.bindStruct(rv.Field(i), errs)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		for _, in := range bindSources {
+			name, ok := f.Tag.Lookup(in)
+			if !ok || name == "" || name == "-" {
+				continue
+			}
+			values := r.paramValues(in, name, f.Type.Kind() == reflect.Slice)
+			if len(values) == 0 {
+				def, ok := f.Tag.Lookup("default")
+				if !ok {
+					break
+				}
+				values = []string{def}
+				if f.Type.Kind() == reflect.Slice {
+					values = splitValues(values)
+				}
+			}
+			if err := setValues(rv.Field(i), values); err != nil {
+				*errs = append(*errs, ParamError{In: in, Name: name, Message: bindErrorMessage(err)})
+			}
+			break
+		}
+	}
+}
+
+// paramValues returns the values of the parameter. When multiple is false, only the last value is returned.
+func (r *Request[T]) paramValues(in, name string, multiple bool) []string {
+	switch in {
+	case BindQuery:
+		if multiple {
+			if values := r.QueryValues(name); len(values) > 0 {
+				return values
+			}
+			if v, ok := r.Query[name]; ok {
+				return splitValues([]string{v})
+			}
+			return nil
+		}
+		if v, ok := r.Query[name]; ok {
+			return []string{v}
+		}
+	case BindPath:
+		if v, ok := r.PathParams[name]; ok {
+			return []string{v}
+		}
+	case BindHeader:
+		if multiple {
+			if values := r.HeaderValues(name); len(values) > 0 {
+				return values
+			}
+			if v, ok := r.Headers.String(name); ok {
+				return splitValues([]string{v})
+			}
+			return nil
+		}
+		if v, ok := r.Headers.String(name); ok {
+			return []string{v}
+		}
+	case BindCookie:
+		if v, ok := r.Cookie(name); ok {
+			return []string{v}
+		}
+	}
+	return nil
+}
+
+func bindErrorMessage(err error) string {
+	var numErr *strconv.NumError
+	if errors.As(err, &numErr) {
+		return fmt.Sprintf("invalid value %q: %s", numErr.Num, numErr.Err)
+	}
+	return err.Error()
+}
@@ -0,0 +1,100 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type bindPagination struct {
+	Limit  int `query:"limit" default:"10"`
+	Offset int `query:"offset" default:"0"`
+}
+
+type bindParams struct {
+	bindPagination
+	ID      int64         `path:"id"`
+	Tags    []string      `query:"tag"`
+	IDs     []int         `query:"ids"`
+	Tenant  string        `header:"X-Tenant"`
+	Session string        `cookie:"session"`
+	Since   time.Time     `query:"since"`
+	Timeout time.Duration `header:"X-Timeout" default:"5s"`
+	Debug   *bool         `query:"debug"`
+	Level   level         `query:"level"`
+	Ignored string
+}
+
+type level int
+
+func (l *level) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "low":
+		*l = 1
+	case "high":
+		*l = 2
+	default:
+		return assert.AnError
+	}
+	return nil
+}
+
+func TestRequest_Bind(t *testing.T) {
+	t.Run("should bind the parameters from every source", func(t *testing.T) {
+		req := &Request[None]{
+			PathParams:      PathParams{"id": "42"},
+			Query:           Query{"limit": "20", "ids": "1,2", "since": "2024-01-02T03:04:05Z", "debug": "true", "level": "high"},
+			MultiValueQuery: MultiValues{"tag": {"a", "b"}},
+			Headers:         NewHeaders(map[string]string{"x-tenant": "acme"}),
+			rawCookies:      []string{"session=abc"},
+		}
+
+		var p bindParams
+		require.NoError(t, req.Bind(&p))
+		assert.Equal(t, int64(42), p.ID)
+		assert.Equal(t, 20, p.Limit)
+		assert.Equal(t, 0, p.Offset)
+		assert.Equal(t, []string{"a", "b"}, p.Tags)
+		assert.Equal(t, []int{1, 2}, p.IDs)
+		assert.Equal(t, "acme", p.Tenant)
+		assert.Equal(t, "abc", p.Session)
+		assert.Equal(t, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), p.Since)
+		assert.Equal(t, 5*time.Second, p.Timeout)
+		require.NotNil(t, p.Debug)
+		assert.True(t, *p.Debug)
+		assert.Equal(t, level(2), p.Level)
+		assert.Empty(t, p.Ignored)
+	})
+
+	t.Run("should report every invalid parameter", func(t *testing.T) {
+		req := &Request[None]{
+			PathParams: PathParams{"id": "abc"},
+			Query:      Query{"limit": "ten", "level": "medium"},
+			Headers:    Headers{"X-Timeout": "soon"},
+		}
+
+		var p bindParams
+		err := req.Bind(&p)
+		var bindErr *BindError
+		require.ErrorAs(t, err, &bindErr)
+		assert.Equal(t, []ParamError{
+			{In: "query", Name: "limit", Message: `invalid value "ten": invalid syntax`},
+			{In: "path", Name: "id", Message: `invalid value "abc": invalid syntax`},
+			{In: "header", Name: "X-Timeout", Message: `time: invalid duration "soon"`},
+			{In: "query", Name: "level", Message: assert.AnError.Error()},
+		}, bindErr.Params)
+
+		resp, rerr := DefaultErrorHandler(context.Background(), err)
+		require.NoError(t, rerr)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("should fail when the target is not a pointer to a struct", func(t *testing.T) {
+		var p bindParams
+		require.Error(t, (&Request[None]{}).Bind(p))
+	})
+}
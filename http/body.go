@@ -0,0 +1,164 @@
+package http
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// ErrBodyTooLarge is the cause of the 413 Request Entity Too Large error returned when the request body exceeds the
+// size set by WithMaxBodySize.
+var ErrBodyTooLarge = errors.New("request body too large")
+
+var errTrailingData = errors.New("unexpected data after the request body")
+
+// DecodeError is returned when the request body cannot be decoded. It implements ErrorResponse as a 400 Bad Request.
+type DecodeError struct {
+	// Offset is the position of the input where the decoding failed, when known.
+	Offset int64
+	// Field is the path of the field that failed to be decoded, when known.
+	Field string
+	// Err is the underlying decoder error. It is not sent to the client.
+	Err error
+}
+
+func (e *DecodeError) Error() string {
+	var sb strings.Builder
+	sb.WriteString("malformed request body")
+	if e.Field != "" {
+		sb.WriteString(" at field ")
+		sb.WriteString(e.Field)
+	}
+	if e.Offset > 0 {
+		sb.WriteString(fmt.Sprintf(" (offset %d)", e.Offset))
+	}
+	if e.Err != nil {
+		sb.WriteString(": ")
+		sb.WriteString(e.Err.Error())
+	}
+	return sb.String()
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+func (e *DecodeError) HttpStatusCode() int {
+	return http.StatusBadRequest
+}
+
+func (e *DecodeError) HttpHeaders() map[string]string {
+	return nil
+}
+
+type decodeErrorBody struct {
+	Message string `json:"message"`
+	Field   string `json:"field,omitempty"`
+	Offset  int64  `json:"offset,omitempty"`
+}
+
+func (e *DecodeError) HttpBody() (json.RawMessage, error) {
+	return json.Marshal(decodeErrorBody{
+		Message: "malformed request body",
+		Field:   e.Field,
+		Offset:  e.Offset,
+	})
+}
+
+// decodeBody decodes the request body with the Decoder registered for its Content-Type. Request types []byte and
+// io.Reader receive the raw body, whatever the Content-Type.
+//
+// Decoding failures are reported as a DecodeError, bodies larger than the limit as a 413 Error and bodies without
+// Decoder as a 415 Error.
+func decodeBody(raw string, isBase64Encoded bool, contentType string, body any, opts *options) error {
+	var reader io.Reader = strings.NewReader(raw)
+	if isBase64Encoded {
+		reader = base64.NewDecoder(base64.StdEncoding, reader)
+	}
+	if opts.maxBodySize > 0 {
+		reader = &maxBytesReader{r: reader, remaining: opts.maxBodySize}
+	}
+
+	err := decodeWith(reader, contentType, body, opts)
+	if err == nil {
+		return nil
+	}
+
+	var errResponse ErrorResponse
+	switch {
+	case errors.Is(err, ErrBodyTooLarge):
+		return &Error{
+			StatusCode: http.StatusRequestEntityTooLarge,
+			Message:    http.StatusText(http.StatusRequestEntityTooLarge),
+			Err:        err,
+		}
+	case errors.Is(err, ErrUnsupportedMediaType):
+		return &Error{
+			StatusCode: http.StatusUnsupportedMediaType,
+			Message:    http.StatusText(http.StatusUnsupportedMediaType),
+			Err:        err,
+		}
+	case errors.As(err, &errResponse):
+		return err
+	}
+	return &DecodeError{Err: err}
+}
+
+func decodeWith(reader io.Reader, contentType string, body any, opts *options) error {
+	if ok, err := decodeRaw(reader, body); ok {
+		return err
+	}
+	mediaType, params, err := parseMediaType(contentType)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrUnsupportedMediaType, err)
+	}
+	d, ok := opts.decoder(mediaType)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnsupportedMediaType, mediaType)
+	}
+	return d.Decode(reader, params, body)
+}
+
+// maxBytesReader fails with ErrBodyTooLarge when more than `remaining` bytes are read.
+type maxBytesReader struct {
+	r         io.Reader
+	remaining int64
+}
+
+func (m *maxBytesReader) Read(p []byte) (int, error) {
+	if m.remaining < 0 {
+		return 0, ErrBodyTooLarge
+	}
+	if int64(len(p)) > m.remaining+1 {
+		p = p[:m.remaining+1]
+	}
+	n, err := m.r.Read(p)
+	m.remaining -= int64(n)
+	if m.remaining < 0 {
+		return n, ErrBodyTooLarge
+	}
+	return n, err
+}
+
+// populateBody will unmarshal the request body into the lambda context and validate it. It is shared by all the Start
+// functions. The body of GET requests is ignored, as is the body of None requests: it is left to the handlers, as
+// Typed handlers decode it themselves.
+func populateBody[Req any, Resp any](method, body string, isBase64Encoded bool, headers Headers, lambdaContext *Context[Req, Resp], opts *options) error {
+	if method == http.MethodGet {
+		return nil
+	}
+	if _, ok := any(lambdaContext.Request.Body).(None); ok {
+		return nil
+	}
+	if len(body) == 0 {
+		return validateBody(&lambdaContext.Request.Body, opts.validator)
+	}
+	if err := decodeBody(body, isBase64Encoded, headers.Get("Content-Type"), &lambdaContext.Request.Body, opts); err != nil {
+		return err
+	}
+	return validateBody(&lambdaContext.Request.Body, opts.validator)
+}
@@ -0,0 +1,105 @@
+package http
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type decodeBodyRequest struct {
+	Name string `json:"name"`
+	Age  int    `json:"age"`
+}
+
+func TestDecodeBody(t *testing.T) {
+	t.Run("should decode the body", func(t *testing.T) {
+		var body decodeBodyRequest
+		require.NoError(t, decodeBody(`{"name":"John","age":30}`, false, "", &body, &options{}))
+		assert.Equal(t, decodeBodyRequest{Name: "John", Age: 30}, body)
+	})
+
+	t.Run("should decode a base64 encoded body", func(t *testing.T) {
+		var body decodeBodyRequest
+		raw := base64.StdEncoding.EncodeToString([]byte(`{"name":"John"}`))
+		require.NoError(t, decodeBody(raw, true, "", &body, &options{}))
+		assert.Equal(t, "John", body.Name)
+	})
+
+	t.Run("should report the offset of syntax errors", func(t *testing.T) {
+		var body decodeBodyRequest
+		err := decodeBody(`{"name":"John",}`, false, "", &body, &options{})
+		var decodeErr *DecodeError
+		require.ErrorAs(t, err, &decodeErr)
+		assert.Equal(t, int64(16), decodeErr.Offset)
+		assert.Equal(t, http.StatusBadRequest, decodeErr.HttpStatusCode())
+	})
+
+	t.Run("should report the field of type errors", func(t *testing.T) {
+		var body decodeBodyRequest
+		err := decodeBody(`{"name":"John","age":"thirty"}`, false, "", &body, &options{})
+		var decodeErr *DecodeError
+		require.ErrorAs(t, err, &decodeErr)
+		assert.Equal(t, "age", decodeErr.Field)
+		assert.Equal(t, int64(29), decodeErr.Offset)
+	})
+
+	t.Run("should report truncated bodies", func(t *testing.T) {
+		var body decodeBodyRequest
+		err := decodeBody(`{"name":`, false, "", &body, &options{})
+		var decodeErr *DecodeError
+		require.ErrorAs(t, err, &decodeErr)
+	})
+
+	t.Run("should accept unknown fields by default", func(t *testing.T) {
+		var body decodeBodyRequest
+		require.NoError(t, decodeBody(`{"name":"John","admin":true}`, false, "", &body, &options{}))
+	})
+
+	t.Run("should reject unknown fields", func(t *testing.T) {
+		var body decodeBodyRequest
+		err := decodeBody(`{"name":"John","admin":true}`, false, "", &body, &options{disallowUnknownFields: true})
+		var decodeErr *DecodeError
+		require.ErrorAs(t, err, &decodeErr)
+		assert.Equal(t, "admin", decodeErr.Field)
+	})
+
+	t.Run("should accept trailing data by default", func(t *testing.T) {
+		var body decodeBodyRequest
+		require.NoError(t, decodeBody(`{"name":"John"} {}`, false, "", &body, &options{}))
+	})
+
+	t.Run("should reject trailing data", func(t *testing.T) {
+		var body decodeBodyRequest
+		err := decodeBody(`{"name":"John"} {}`, false, "", &body, &options{disallowTrailingData: true})
+		var decodeErr *DecodeError
+		require.ErrorAs(t, err, &decodeErr)
+		assert.ErrorIs(t, err, errTrailingData)
+
+		require.NoError(t, decodeBody("{\"name\":\"John\"}\n", false, "", &body, &options{disallowTrailingData: true}))
+	})
+
+	t.Run("should reject bodies larger than the limit", func(t *testing.T) {
+		var body decodeBodyRequest
+		err := decodeBody(`{"name":"John Doe"}`, false, "", &body, &options{maxBodySize: 10})
+		require.ErrorIs(t, err, ErrBodyTooLarge)
+		resp, herr := DefaultErrorHandler(context.Background(), err)
+		require.NoError(t, herr)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+	})
+
+	t.Run("should accept bodies with the size of the limit", func(t *testing.T) {
+		var body decodeBodyRequest
+		raw := `{"name":"John"}`
+		require.NoError(t, decodeBody(raw, false, "", &body, &options{maxBodySize: int64(len(raw))}))
+	})
+}
+
+func TestDecodeError_HttpBody(t *testing.T) {
+	b, err := (&DecodeError{Field: "age", Offset: 29}).HttpBody()
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"message":"malformed request body","field":"age","offset":29}`, string(b))
+}
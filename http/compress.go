@@ -0,0 +1,197 @@
+package http
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// DefaultCompressionThreshold is the minimum size, in bytes, of the bodies compressed by Compress.
+const DefaultCompressionThreshold = 1024
+
+// Compressor wraps w with a writer that compresses the data written to it. The returned writer is closed once the
+// whole body is written.
+type Compressor func(w io.Writer) (io.WriteCloser, error)
+
+type compressionOptions struct {
+	threshold   int
+	level       int
+	encodings   []string
+	compressors map[string]Compressor
+}
+
+// CompressionOption configures the Compress middleware.
+type CompressionOption func(*compressionOptions)
+
+// WithCompressionThreshold sets the minimum size, in bytes, of the bodies to compress. Smaller bodies are sent as is.
+// Defaults to DefaultCompressionThreshold.
+func WithCompressionThreshold(n int) CompressionOption {
+	return func(o *compressionOptions) {
+		o.threshold = n
+	}
+}
+
+// WithCompressionLevel sets the level used by the gzip and deflate compressors (Eg: gzip.BestSpeed). Defaults to
+// gzip.DefaultCompression.
+func WithCompressionLevel(level int) CompressionOption {
+	return func(o *compressionOptions) {
+		o.level = level
+	}
+}
+
+// WithCompressor registers a compressor for the given content coding (Eg: "br"). Registered compressors are preferred
+// over the built-in ones when the client accepts them with the same quality. Registering a compressor for "gzip" or
+// "deflate" replaces the built-in one.
+func WithCompressor(encoding string, compressor Compressor) CompressionOption {
+	return func(o *compressionOptions) {
+		encoding = strings.ToLower(encoding)
+		if _, ok := o.compressors[encoding]; !ok {
+			o.encodings = append(o.encodings, encoding)
+		}
+		o.compressors[encoding] = compressor
+	}
+}
+
+// Compress returns a middleware that compresses the response body according to the Accept-Encoding header of the
+// request. gzip and deflate are supported out of the box, other codings (Eg: brotli) can be added with
+// WithCompressor.
+//
+// Only successful responses with a textual Content-Type, or none, and a body of at least the threshold are
+// compressed. Responses that already have a Content-Encoding are left untouched. A compressed body is sent to API
+// Gateway base64 encoded, with the Content-Encoding header set and Accept-Encoding added to the Vary header.
+//
+// Streamed responses (see WithResponseStreaming) are not compressed: their headers and part of their body may already
+// have been sent when the handler returns.
+func Compress[Req any, Resp any](opts ...CompressionOption) Middleware[Req, Resp] {
+	o := compressionOptions{
+		threshold:   DefaultCompressionThreshold,
+		level:       gzip.DefaultCompression,
+		compressors: make(map[string]Compressor),
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if _, ok := o.compressors["gzip"]; !ok {
+		o.encodings = append(o.encodings, "gzip")
+		o.compressors["gzip"] = func(w io.Writer) (io.WriteCloser, error) {
+			return gzip.NewWriterLevel(w, o.level)
+		}
+	}
+	if _, ok := o.compressors["deflate"]; !ok {
+		o.encodings = append(o.encodings, "deflate")
+		o.compressors["deflate"] = func(w io.Writer) (io.WriteCloser, error) {
+			return zlib.NewWriterLevel(w, o.level)
+		}
+	}
+
+	return func(ctx *Context[Req, Resp], next Handler[Req, Resp]) error {
+		err := next(ctx)
+		if ctx.Response.Err != nil || (err != nil && !errors.Is(err, ctx.Response)) || ctx.Response.Streaming() {
+			return err
+		}
+		if compressErr := compressResponse(ctx.Request.Headers, ctx.Response, &o); compressErr != nil {
+			ctx.Response.Err = compressErr
+			return ctx.Response
+		}
+		return err
+	}
+}
+
+func compressResponse[T any](headers map[string]string, r *Response[T], o *compressionOptions) error {
+	if headerValue(r.Headers, "Content-Encoding") != "" {
+		return nil
+	}
+	contentType := headerValue(r.Headers, "Content-Type")
+	if contentType != "" && !isTextual(contentType) {
+		return nil
+	}
+	body, err := r.ReadBody()
+	if err != nil {
+		return err
+	}
+	if len(body) < o.threshold {
+		return nil
+	}
+	addVary(r, "Accept-Encoding")
+
+	encoding, ok := negotiateEncoding(headerValue(headers, "Accept-Encoding"), o.encodings)
+	if !ok {
+		return nil
+	}
+	var buf bytes.Buffer
+	w, err := o.compressors[encoding](&buf)
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(body); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	r.Bytes(contentType, buf.Bytes())
+	if contentType == "" {
+		delete(r.Headers, "Content-Type")
+	}
+	r.setHeader("Content-Encoding", encoding)
+	return nil
+}
+
+// negotiateEncoding picks, among the available content codings, the one with the highest quality in the
+// Accept-Encoding header. Ties are broken by the order of the available codings. "identity" is never picked, as it
+// means no compression.
+func negotiateEncoding(acceptEncoding string, available []string) (string, bool) {
+	qualities := make(map[string]float64)
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		if coding == "" {
+			continue
+		}
+		q := 1.0
+		for _, param := range strings.Split(params, ";") {
+			k, v, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if strings.EqualFold(k, "q") {
+				if parsed, err := strconv.ParseFloat(v, 64); err == nil && parsed >= 0 && parsed <= 1 {
+					q = parsed
+				}
+			}
+		}
+		qualities[coding] = q
+	}
+
+	var (
+		best  string
+		bestQ float64
+	)
+	for _, encoding := range available {
+		q, ok := qualities[encoding]
+		if !ok {
+			if q, ok = qualities["*"]; !ok {
+				continue
+			}
+		}
+		if q > bestQ {
+			best, bestQ = encoding, q
+		}
+	}
+	return best, best != ""
+}
+
+// addVary adds the header name to the Vary header of the response, unless it is already there.
+func addVary[T any](r *Response[T], name string) {
+	vary := headerValue(r.Headers, "Vary")
+	for _, v := range strings.Split(vary, ",") {
+		if strings.EqualFold(strings.TrimSpace(v), name) {
+			return
+		}
+	}
+	if vary != "" {
+		name = vary + ", " + name
+	}
+	r.setHeader("Vary", name)
+}
@@ -0,0 +1,134 @@
+package http
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/base64"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func textHandler(body string) Handler[None, None] {
+	return func(ctx *Context[None, None]) error {
+		return ctx.Response.SendString(body)
+	}
+}
+
+func TestCompress(t *testing.T) {
+	large := strings.Repeat("lambda ", 500)
+
+	t.Run("should gzip the body when accepted", func(t *testing.T) {
+		ctx := newRouterContext(http.MethodGet, "/")
+		ctx.Request.Headers = Headers{"Accept-Encoding": "gzip, deflate"}
+		err := Use(textHandler(large), Compress[None, None]())(ctx)
+		require.ErrorIs(t, err, ctx.Response)
+
+		assert.Equal(t, "gzip", ctx.Response.Headers["Content-Encoding"])
+		assert.Equal(t, "Accept-Encoding", ctx.Response.Headers["Vary"])
+		assert.Equal(t, "text/plain; charset=utf-8", ctx.Response.Headers["Content-Type"])
+
+		body, isBase64Encoded, err := ctx.Response.GatewayBody()
+		require.NoError(t, err)
+		assert.True(t, isBase64Encoded)
+		raw, err := base64.StdEncoding.DecodeString(body)
+		require.NoError(t, err)
+		zr, err := gzip.NewReader(bytes.NewReader(raw))
+		require.NoError(t, err)
+		decompressed, err := io.ReadAll(zr)
+		require.NoError(t, err)
+		assert.Equal(t, large, string(decompressed))
+	})
+
+	t.Run("should pick the coding with the highest quality", func(t *testing.T) {
+		ctx := newRouterContext(http.MethodGet, "/")
+		ctx.Request.Headers = Headers{"Accept-Encoding": "gzip;q=0.5, deflate"}
+		err := Use(textHandler(large), Compress[None, None]())(ctx)
+		require.ErrorIs(t, err, ctx.Response)
+
+		assert.Equal(t, "deflate", ctx.Response.Headers["Content-Encoding"])
+		zr, err := zlib.NewReader(&ctx.Response.Body)
+		require.NoError(t, err)
+		decompressed, err := io.ReadAll(zr)
+		require.NoError(t, err)
+		assert.Equal(t, large, string(decompressed))
+	})
+
+	t.Run("should use registered compressors", func(t *testing.T) {
+		ctx := newRouterContext(http.MethodGet, "/")
+		ctx.Request.Headers = Headers{"Accept-Encoding": "br, gzip"}
+		compressor := func(w io.Writer) (io.WriteCloser, error) {
+			return nopWriteCloser{w}, nil
+		}
+		err := Use(textHandler(large), Compress[None, None](WithCompressor("br", compressor)))(ctx)
+		require.ErrorIs(t, err, ctx.Response)
+		assert.Equal(t, "br", ctx.Response.Headers["Content-Encoding"])
+	})
+
+	t.Run("should not compress bodies below the threshold", func(t *testing.T) {
+		ctx := newRouterContext(http.MethodGet, "/")
+		ctx.Request.Headers = Headers{"Accept-Encoding": "gzip"}
+		err := Use(textHandler("small"), Compress[None, None]())(ctx)
+		require.ErrorIs(t, err, ctx.Response)
+		assert.Empty(t, ctx.Response.Headers["Content-Encoding"])
+		assert.Equal(t, "small", ctx.Response.Body.String())
+		assert.False(t, ctx.Response.IsBinary())
+	})
+
+	t.Run("should only add Vary when no coding is acceptable", func(t *testing.T) {
+		ctx := newRouterContext(http.MethodGet, "/")
+		ctx.Request.Headers = Headers{"Accept-Encoding": "identity"}
+		err := Use(textHandler(large), Compress[None, None](WithCompressionThreshold(10)))(ctx)
+		require.ErrorIs(t, err, ctx.Response)
+		assert.Empty(t, ctx.Response.Headers["Content-Encoding"])
+		assert.Equal(t, "Accept-Encoding", ctx.Response.Headers["Vary"])
+		assert.Equal(t, large, ctx.Response.Body.String())
+	})
+
+	t.Run("should append to an existing Vary header", func(t *testing.T) {
+		ctx := newRouterContext(http.MethodGet, "/")
+		ctx.Request.Headers = Headers{"Accept-Encoding": "*"}
+		err := Use(func(ctx *Context[None, None]) error {
+			ctx.Response.Header("Vary", "Origin")
+			return ctx.Response.SendString(large)
+		}, Compress[None, None]())(ctx)
+		require.ErrorIs(t, err, ctx.Response)
+		assert.Equal(t, "gzip", ctx.Response.Headers["Content-Encoding"])
+		assert.Equal(t, "Origin, Accept-Encoding", ctx.Response.Headers["Vary"])
+	})
+
+	t.Run("should not compress binary content types", func(t *testing.T) {
+		ctx := newRouterContext(http.MethodGet, "/")
+		ctx.Request.Headers = Headers{"Accept-Encoding": "gzip"}
+		err := Use(func(ctx *Context[None, None]) error {
+			return ctx.Response.Bytes("image/png", []byte(large))
+		}, Compress[None, None]())(ctx)
+		require.ErrorIs(t, err, ctx.Response)
+		assert.Empty(t, ctx.Response.Headers["Content-Encoding"])
+	})
+
+	t.Run("should not touch failed responses", func(t *testing.T) {
+		ctx := newRouterContext(http.MethodGet, "/")
+		ctx.Request.Headers = Headers{"Accept-Encoding": "gzip"}
+		failure := errors.New("failure")
+		err := Use(func(ctx *Context[None, None]) error {
+			return failure
+		}, Compress[None, None]())(ctx)
+		require.ErrorIs(t, err, failure)
+		assert.Empty(t, ctx.Response.Headers["Content-Encoding"])
+	})
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error {
+	return nil
+}
@@ -3,6 +3,8 @@ package http
 import (
 	"context"
 	"errors"
+
+	"github.com/jamillosantos/lambda/internal/resources"
 )
 
 type Context[Req any, Resp any] struct {
@@ -11,6 +13,9 @@ type Context[Req any, Resp any] struct {
 	Response *Response[Resp]
 	Locals   map[string]any
 	error    error
+
+	// opts are the options of the Start function, used by Typed to decode the request.
+	opts *options
 }
 
 func (l *Context[Req, Resp]) Error() string {
@@ -25,6 +30,15 @@ func (l *Context[Req, Resp]) SetLocal(key string, value any) *Context[Req, Resp]
 	return l
 }
 
+// PutLocal stores the value in the locals. Unlike SetLocal, it does not return the Context, so both Context types
+// implement lambda.Locals.
+func (l *Context[Req, Resp]) PutLocal(key string, value any) {
+	if l.Locals == nil {
+		l.Locals = make(map[string]any)
+	}
+	l.Locals[key] = value
+}
+
 func (l *Context[Req, Resp]) GetLocal(key string) (any, bool) {
 	value, ok := l.Locals[key]
 	return value, ok
@@ -38,3 +52,23 @@ func (l *Context[Req, Resp]) UnsetLocal(key string) *Context[Req, Resp] {
 func (l *Context[Req, Resp]) Is(err error) bool {
 	return errors.Is(l.error, err)
 }
+
+// ResourceReady reports whether the resource with the given name was started successfully.
+func (l *Context[Req, Resp]) ResourceReady(name string) bool {
+	return resources.Ready(l.Context, name)
+}
+
+// RequireResource starts the resource with the given name, and its dependencies, if it was not started yet. It is how
+// resources with LazyPolicy are started.
+func (l *Context[Req, Resp]) RequireResource(name string) error {
+	return resources.Require(l.Context, name)
+}
+
+// Resource returns the resource with the given name. Prefer ResourceOf for a typed access.
+func (l *Context[Req, Resp]) Resource(name string) (any, error) {
+	r, err := resources.Get(l.Context, name)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
@@ -38,3 +38,17 @@ func TestContext_UnsetLocal(t *testing.T) {
 	assert.Equal(t, l.UnsetLocal("key"), l)
 	assert.Equal(t, map[string]any{}, l.Locals)
 }
+
+func TestContext_PutLocal(t *testing.T) {
+	t.Run("should store the value", func(t *testing.T) {
+		l := &Context[None, None]{Locals: map[string]any{}}
+		l.PutLocal("key", "value")
+		assert.Equal(t, map[string]any{"key": "value"}, l.Locals)
+	})
+
+	t.Run("should initialize the locals", func(t *testing.T) {
+		l := &Context[None, None]{}
+		l.PutLocal("key", "value")
+		assert.Equal(t, map[string]any{"key": "value"}, l.Locals)
+	})
+}
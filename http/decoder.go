@@ -0,0 +1,247 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+const (
+	MIMEApplicationJSON = "application/json"
+	MIMEApplicationForm = "application/x-www-form-urlencoded"
+	MIMEMultipartForm   = "multipart/form-data"
+	MIMETextPlain       = "text/plain"
+)
+
+// multipartMemory is the memory used to keep multipart files before they are stored in temporary files. It is above
+// the 6MB payload limit of Lambda, so the files of a request are always kept in memory.
+const multipartMemory = 32 << 20
+
+// ErrUnsupportedMediaType is the cause of the 415 Unsupported Media Type error returned when there is no Decoder for
+// the Content-Type of the request.
+var ErrUnsupportedMediaType = errors.New("unsupported media type")
+
+// Decoder decodes a request body of a media type. v is a pointer to the Request.Body and params are the parameters of
+// the Content-Type header (Eg: charset).
+type Decoder interface {
+	Decode(r io.Reader, params map[string]string, v any) error
+}
+
+// DecoderFunc is a function that implements Decoder.
+type DecoderFunc func(r io.Reader, params map[string]string, v any) error
+
+func (f DecoderFunc) Decode(r io.Reader, params map[string]string, v any) error {
+	return f(r, params, v)
+}
+
+// decoder returns the Decoder for the given media type. Decoders registered with WithDecoder take precedence over the
+// built-in ones. Media types with the "+json" suffix (Eg: application/merge-patch+json) are decoded as JSON.
+func (o *options) decoder(mediaType string) (Decoder, bool) {
+	if d, ok := o.decoders[mediaType]; ok {
+		return d, true
+	}
+	switch {
+	case mediaType == MIMEApplicationJSON, strings.HasSuffix(mediaType, "+json"):
+		return &jsonDecoder{
+			disallowUnknownFields: o.disallowUnknownFields,
+			disallowTrailingData:  o.disallowTrailingData,
+		}, true
+	case mediaType == MIMEApplicationForm:
+		return DecoderFunc(decodeForm), true
+	case mediaType == MIMEMultipartForm:
+		return DecoderFunc(decodeMultipart), true
+	case mediaType == MIMETextPlain:
+		return DecoderFunc(decodeText), true
+	}
+	return nil, false
+}
+
+type jsonDecoder struct {
+	disallowUnknownFields bool
+	disallowTrailingData  bool
+}
+
+func (d *jsonDecoder) Decode(r io.Reader, _ map[string]string, v any) error {
+	dec := json.NewDecoder(r)
+	if d.disallowUnknownFields {
+		dec.DisallowUnknownFields()
+	}
+	if err := dec.Decode(v); err != nil {
+		return newJSONDecodeError(dec, err)
+	}
+	if d.disallowTrailingData {
+		var trailing json.RawMessage
+		if err := dec.Decode(&trailing); !errors.Is(err, io.EOF) {
+			if err == nil {
+				err = errTrailingData
+			}
+			return newJSONDecodeError(dec, err)
+		}
+	}
+	return nil
+}
+
+func newJSONDecodeError(dec *json.Decoder, err error) error {
+	if errors.Is(err, ErrBodyTooLarge) {
+		return err
+	}
+	decodeErr := &DecodeError{
+		Offset: dec.InputOffset(),
+		Err:    err,
+	}
+	var (
+		syntaxErr *json.SyntaxError
+		typeErr   *json.UnmarshalTypeError
+	)
+	switch {
+	case errors.As(err, &syntaxErr):
+		decodeErr.Offset = syntaxErr.Offset
+	case errors.As(err, &typeErr):
+		decodeErr.Offset = typeErr.Offset
+		decodeErr.Field = typeErr.Field
+	default:
+		// The error for unknown fields is not typed: `json: unknown field "name"`.
+		if field, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+			decodeErr.Field = strings.Trim(field, `"`)
+		}
+	}
+	return decodeErr
+}
+
+// decodeForm decodes an application/x-www-form-urlencoded body into a url.Values, a map[string][]string, a
+// map[string]string or a struct, using the `form` tag of its fields.
+func decodeForm(r io.Reader, _ map[string]string, v any) error {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	values, err := url.ParseQuery(string(b))
+	if err != nil {
+		return err
+	}
+	return bindForm(values, nil, v)
+}
+
+// decodeMultipart decodes a multipart/form-data body into a struct, using the `form` tag of its fields. Files are set
+// into fields of type *multipart.FileHeader or []*multipart.FileHeader.
+//
+// Files are kept in memory. The temporary files created for parts that do not fit in multipartMemory are removed
+// once the body is decoded, so they do not pile up in /tmp across the invocations of a warm execution environment.
+func decodeMultipart(r io.Reader, params map[string]string, v any) error {
+	boundary, ok := params["boundary"]
+	if !ok {
+		return errors.New("missing multipart boundary")
+	}
+	form, err := multipart.NewReader(r, boundary).ReadForm(multipartMemory)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = form.RemoveAll()
+	}()
+	return bindForm(form.Value, form.File, v)
+}
+
+// decodeText decodes a text/plain body into a string or a named string type.
+func decodeText(r io.Reader, _ map[string]string, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.Elem().Kind() != reflect.String {
+		return fmt.Errorf("%w: text/plain can only be decoded into a string, not %T", ErrUnsupportedMediaType, v)
+	}
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	rv.Elem().SetString(string(b))
+	return nil
+}
+
+var fileHeaderType = reflect.TypeFor[*multipart.FileHeader]()
+
+func bindForm(values map[string][]string, files map[string][]*multipart.FileHeader, v any) error {
+	switch m := v.(type) {
+	case *url.Values:
+		*m = values
+		return nil
+	case *map[string][]string:
+		*m = values
+		return nil
+	case *map[string]string:
+		*m = make(map[string]string, len(values))
+		for k, vs := range values {
+			if len(vs) > 0 {
+				(*m)[k] = vs[0]
+			}
+		}
+		return nil
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("%w: forms can only be decoded into a struct or a map, not %T", ErrUnsupportedMediaType, v)
+	}
+	rv = rv.Elem()
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		f := rt.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, ok := fieldName(f, "form")
+		if !ok {
+			continue
+		}
+		field := rv.Field(i)
+		switch {
+		case f.Type == fileHeaderType:
+			if fhs := files[name]; len(fhs) > 0 {
+				field.Set(reflect.ValueOf(fhs[0]))
+			}
+		case f.Type == reflect.SliceOf(fileHeaderType):
+			if fhs := files[name]; len(fhs) > 0 {
+				field.Set(reflect.ValueOf(fhs))
+			}
+		default:
+			if err := setValues(field, values[name]); err != nil {
+				return &DecodeError{Field: name, Err: err}
+			}
+		}
+	}
+	return nil
+}
+
+// decodeRaw sets the body when the request type is []byte or io.Reader. It reports whether the body was set.
+func decodeRaw(r io.Reader, v any) (bool, error) {
+	switch b := v.(type) {
+	case *[]byte:
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return true, err
+		}
+		*b = data
+		return true, nil
+	case *io.Reader:
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return true, err
+		}
+		*b = bytes.NewReader(data)
+		return true, nil
+	}
+	return false, nil
+}
+
+// parseMediaType parses the Content-Type header. Requests without Content-Type are considered JSON.
+func parseMediaType(contentType string) (string, map[string]string, error) {
+	if contentType == "" {
+		return MIMEApplicationJSON, nil, nil
+	}
+	return mime.ParseMediaType(contentType)
+}
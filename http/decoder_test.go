@@ -0,0 +1,174 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type signupForm struct {
+	Name     string        `form:"name"`
+	Age      int           `form:"age"`
+	Tags     []string      `form:"tag"`
+	Born     time.Time     `form:"born"`
+	Timeout  time.Duration `form:"timeout"`
+	Nickname *string       `json:"nickname"`
+	Ignored  string        `form:"-"`
+}
+
+func TestDecodeBody_json(t *testing.T) {
+	t.Run("should decode +json media types", func(t *testing.T) {
+		var body decodeBodyRequest
+		require.NoError(t, decodeBody(`{"name":"John"}`, false, "application/merge-patch+json", &body, &options{}))
+		assert.Equal(t, "John", body.Name)
+	})
+
+	t.Run("should ignore the media type parameters", func(t *testing.T) {
+		var body decodeBodyRequest
+		require.NoError(t, decodeBody(`{"name":"John"}`, false, "application/json; charset=utf-8", &body, &options{}))
+		assert.Equal(t, "John", body.Name)
+	})
+}
+
+func TestDecodeBody_form(t *testing.T) {
+	t.Run("should decode into a struct", func(t *testing.T) {
+		var body signupForm
+		raw := "name=John&age=30&tag=a&tag=b&born=2000-01-02T03:04:05Z&timeout=1m&nickname=johnny&Ignored=x"
+		require.NoError(t, decodeBody(raw, false, MIMEApplicationForm, &body, &options{}))
+		nickname := "johnny"
+		assert.Equal(t, signupForm{
+			Name:     "John",
+			Age:      30,
+			Tags:     []string{"a", "b"},
+			Born:     time.Date(2000, 1, 2, 3, 4, 5, 0, time.UTC),
+			Timeout:  time.Minute,
+			Nickname: &nickname,
+		}, body)
+	})
+
+	t.Run("should decode into url.Values", func(t *testing.T) {
+		var body url.Values
+		require.NoError(t, decodeBody("tag=a&tag=b", false, MIMEApplicationForm, &body, &options{}))
+		assert.Equal(t, url.Values{"tag": {"a", "b"}}, body)
+	})
+
+	t.Run("should report the invalid field", func(t *testing.T) {
+		var body signupForm
+		err := decodeBody("age=thirty", false, MIMEApplicationForm, &body, &options{})
+		var decodeErr *DecodeError
+		require.ErrorAs(t, err, &decodeErr)
+		assert.Equal(t, "age", decodeErr.Field)
+	})
+}
+
+type uploadForm struct {
+	Description string                  `form:"description"`
+	Avatar      *multipart.FileHeader   `form:"avatar"`
+	Attachments []*multipart.FileHeader `form:"attachment"`
+}
+
+func TestDecodeBody_multipart(t *testing.T) {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	require.NoError(t, w.WriteField("description", "my files"))
+	for _, f := range []struct{ field, name, content string }{
+		{"avatar", "avatar.png", "png data"},
+		{"attachment", "a.txt", "a"},
+		{"attachment", "b.txt", "b"},
+	} {
+		fw, err := w.CreateFormFile(f.field, f.name)
+		require.NoError(t, err)
+		_, err = fw.Write([]byte(f.content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+
+	var body uploadForm
+	require.NoError(t, decodeBody(buf.String(), false, w.FormDataContentType(), &body, &options{}))
+	assert.Equal(t, "my files", body.Description)
+	require.NotNil(t, body.Avatar)
+	assert.Equal(t, "avatar.png", body.Avatar.Filename)
+	f, err := body.Avatar.Open()
+	require.NoError(t, err)
+	content, err := io.ReadAll(f)
+	require.NoError(t, err)
+	assert.Equal(t, "png data", string(content))
+	require.Len(t, body.Attachments, 2)
+	assert.Equal(t, "b.txt", body.Attachments[1].Filename)
+}
+
+func TestDecodeBody_text(t *testing.T) {
+	t.Run("should decode into a string", func(t *testing.T) {
+		var body string
+		require.NoError(t, decodeBody("hello world", false, "text/plain; charset=utf-8", &body, &options{}))
+		assert.Equal(t, "hello world", body)
+	})
+
+	t.Run("should decode into a named string type", func(t *testing.T) {
+		type message string
+		var body message
+		require.NoError(t, decodeBody("hello world", false, MIMETextPlain, &body, &options{}))
+		assert.Equal(t, message("hello world"), body)
+	})
+
+	t.Run("should fail with 415 when the body is not a string", func(t *testing.T) {
+		var body decodeBodyRequest
+		err := decodeBody("hello world", false, MIMETextPlain, &body, &options{})
+		require.ErrorIs(t, err, ErrUnsupportedMediaType)
+	})
+}
+
+func TestDecodeBody_raw(t *testing.T) {
+	t.Run("should set the raw bytes whatever the content type", func(t *testing.T) {
+		var body []byte
+		require.NoError(t, decodeBody("\x89PNG", false, "image/png", &body, &options{}))
+		assert.Equal(t, []byte("\x89PNG"), body)
+	})
+
+	t.Run("should set a reader", func(t *testing.T) {
+		var body io.Reader
+		require.NoError(t, decodeBody("data", false, "application/octet-stream", &body, &options{}))
+		content, err := io.ReadAll(body)
+		require.NoError(t, err)
+		assert.Equal(t, "data", string(content))
+	})
+}
+
+func TestDecodeBody_unsupported(t *testing.T) {
+	var body decodeBodyRequest
+	err := decodeBody("<user/>", false, "application/xml", &body, &options{})
+	require.ErrorIs(t, err, ErrUnsupportedMediaType)
+	resp, herr := DefaultErrorHandler(context.Background(), err)
+	require.NoError(t, herr)
+	assert.Equal(t, http.StatusUnsupportedMediaType, resp.StatusCode)
+}
+
+func TestWithDecoder(t *testing.T) {
+	type user struct {
+		Name string `xml:"name"`
+	}
+	o := &options{}
+	WithDecoder("Application/XML", DecoderFunc(func(r io.Reader, _ map[string]string, v any) error {
+		return xml.NewDecoder(r).Decode(v)
+	}))(o)
+
+	var body user
+	require.NoError(t, decodeBody("<user><name>John</name></user>", false, "application/xml", &body, o))
+	assert.Equal(t, "John", body.Name)
+}
+
+func TestHeaderValue(t *testing.T) {
+	assert.Equal(t, "application/json", headerValue(map[string]string{"content-type": "application/json"}, "Content-Type"))
+	assert.Equal(t, "", headerValue(map[string]string{"accept": "*/*"}, "Content-Type"))
+	assert.Equal(t, "", headerValue(nil, strings.ToLower("Content-Type")))
+}
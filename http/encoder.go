@@ -0,0 +1,173 @@
+package http
+
+import (
+	"encoding"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+const (
+	MIMEApplicationXML     = "application/xml"
+	MIMETextXML            = "text/xml"
+	MIMEApplicationMsgPack = "application/msgpack"
+	MIMETextCSV            = "text/csv"
+	MIMETextHTML           = "text/html"
+)
+
+// Encoder encodes a response body of a media type.
+type Encoder interface {
+	Encode(w io.Writer, v any) error
+}
+
+// EncoderFunc is a function that implements Encoder.
+type EncoderFunc func(w io.Writer, v any) error
+
+func (f EncoderFunc) Encode(w io.Writer, v any) error {
+	return f(w, v)
+}
+
+// XMLEncoder encodes response bodies with encoding/xml. It is not a built-in encoder, as encoding/xml cannot encode
+// maps and most types without `xml` tags, and browsers accept XML: register it with WithEncoder for the XML media
+// types that must be served.
+//
+//	http.StartV2(handler, http.WithEncoder(http.MIMEApplicationXML, http.XMLEncoder))
+var XMLEncoder Encoder = EncoderFunc(encodeXML)
+
+// builtinEncoders are the encoders available by default, in order of preference of the server.
+var builtinEncoders = []struct {
+	mediaType string
+	encoder   Encoder
+}{
+	{MIMEApplicationJSON, EncoderFunc(encodeJSON)},
+	{MIMETextPlain, EncoderFunc(encodeText)},
+	{MIMEApplicationMsgPack, EncoderFunc(encodeMsgPack)},
+	{"application/x-msgpack", EncoderFunc(encodeMsgPack)},
+	{MIMETextCSV, EncoderFunc(encodeCSV)},
+}
+
+// encoders returns the available media types, in order of preference, and the Encoder for each of them. Encoders
+// registered with WithEncoder replace the built-in ones of the same media type and are preferred after them.
+func encoders(custom map[string]Encoder) ([]string, map[string]Encoder) {
+	mediaTypes := make([]string, 0, len(builtinEncoders)+len(custom))
+	byMediaType := make(map[string]Encoder, len(builtinEncoders)+len(custom))
+	for _, e := range builtinEncoders {
+		mediaTypes = append(mediaTypes, e.mediaType)
+		byMediaType[e.mediaType] = e.encoder
+	}
+	customTypes := make([]string, 0, len(custom))
+	for mediaType := range custom {
+		customTypes = append(customTypes, mediaType)
+	}
+	sort.Strings(customTypes)
+	for _, mediaType := range customTypes {
+		if _, ok := byMediaType[mediaType]; !ok {
+			mediaTypes = append(mediaTypes, mediaType)
+		}
+		byMediaType[mediaType] = custom[mediaType]
+	}
+	return mediaTypes, byMediaType
+}
+
+// contentType returns the Content-Type header for the media type. Text media types are sent as UTF-8.
+func contentType(mediaType string) string {
+	if strings.HasPrefix(mediaType, "text/") {
+		return mediaType + "; charset=utf-8"
+	}
+	return mediaType
+}
+
+func encodeJSON(w io.Writer, v any) error {
+	return json.NewEncoder(w).Encode(v)
+}
+
+func encodeXML(w io.Writer, v any) error {
+	return xml.NewEncoder(w).Encode(v)
+}
+
+// encodeText writes strings, []byte, errors and fmt.Stringer as is. Other values are formatted with fmt.Sprint.
+func encodeText(w io.Writer, v any) error {
+	var err error
+	switch t := v.(type) {
+	case string:
+		_, err = io.WriteString(w, t)
+	case []byte:
+		_, err = w.Write(t)
+	case encoding.TextMarshaler:
+		var b []byte
+		if b, err = t.MarshalText(); err == nil {
+			_, err = w.Write(b)
+		}
+	default:
+		_, err = fmt.Fprint(w, v)
+	}
+	return err
+}
+
+// encodeCSV writes a [][]string as is, or a slice of structs as a header row, using the `csv` tag or the name of the
+// fields, followed by one row per element.
+func encodeCSV(w io.Writer, v any) error {
+	cw := csv.NewWriter(w)
+	if records, ok := v.([][]string); ok {
+		return cw.WriteAll(records)
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return fmt.Errorf("csv: unsupported type %T", v)
+	}
+	elemType := rv.Type().Elem()
+	for elemType.Kind() == reflect.Pointer {
+		elemType = elemType.Elem()
+	}
+	if elemType.Kind() != reflect.Struct {
+		return fmt.Errorf("csv: unsupported type %T", v)
+	}
+
+	var (
+		header []string
+		fields []int
+	)
+	for i := 0; i < elemType.NumField(); i++ {
+		f := elemType.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, ok := fieldName(f, "csv")
+		if !ok {
+			continue
+		}
+		header = append(header, name)
+		fields = append(fields, i)
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	record := make([]string, len(fields))
+	for i := 0; i < rv.Len(); i++ {
+		elem := rv.Index(i)
+		for elem.Kind() == reflect.Pointer {
+			elem = elem.Elem()
+		}
+		if !elem.IsValid() {
+			continue
+		}
+		for j, f := range fields {
+			var sb strings.Builder
+			if err := encodeText(&sb, elem.Field(f).Interface()); err != nil {
+				return err
+			}
+			record[j] = sb.String()
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
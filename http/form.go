@@ -0,0 +1,125 @@
+package http
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+	timeType            = reflect.TypeFor[time.Time]()
+	durationType        = reflect.TypeFor[time.Duration]()
+)
+
+// fieldName returns the name of the struct field for the given tag. It falls back to the json tag and then to the
+// field name. It returns false when the field is ignored with "-".
+func fieldName(f reflect.StructField, tag string) (string, bool) {
+	for _, t := range []string{tag, "json"} {
+		v, ok := f.Tag.Lookup(t)
+		if !ok {
+			continue
+		}
+		name, _, _ := strings.Cut(v, ",")
+		if name == "-" {
+			return "", false
+		}
+		if name != "" {
+			return name, true
+		}
+	}
+	return f.Name, true
+}
+
+// setValues sets the field with the given values. Slices receive all values, any other type the first one.
+func setValues(field reflect.Value, values []string) error {
+	if len(values) == 0 {
+		return nil
+	}
+	if field.Kind() == reflect.Slice && !field.Type().Implements(textUnmarshalerType) &&
+		!reflect.PointerTo(field.Type()).Implements(textUnmarshalerType) && field.Type().Elem().Kind() != reflect.Uint8 {
+		slice := reflect.MakeSlice(field.Type(), len(values), len(values))
+		for i, v := range values {
+			if err := setValue(slice.Index(i), v); err != nil {
+				return err
+			}
+		}
+		field.Set(slice)
+		return nil
+	}
+	return setValue(field, values[0])
+}
+
+// setValue parses the string into the field. It supports the types implementing encoding.TextUnmarshaler, strings,
+// booleans, numbers, time.Time (RFC 3339), time.Duration and pointers to them.
+func setValue(field reflect.Value, value string) error {
+	if field.Kind() == reflect.Pointer {
+		if field.IsNil() {
+			field.Set(reflect.New(field.Type().Elem()))
+		}
+		return setValue(field.Elem(), value)
+	}
+	if field.CanAddr() {
+		if u, ok := field.Addr().Interface().(encoding.TextUnmarshaler); ok && field.Type() != timeType {
+			return u.UnmarshalText([]byte(value))
+		}
+	}
+
+	switch field.Type() {
+	case timeType:
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return err
+		}
+		field.Set(reflect.ValueOf(t))
+		return nil
+	case durationType:
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(d))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(value, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(value, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f)
+	case reflect.Slice:
+		if field.Type().Elem().Kind() == reflect.Uint8 {
+			field.SetBytes([]byte(value))
+			return nil
+		}
+		return setValues(field, []string{value})
+	default:
+		return fmt.Errorf("unsupported type %s", field.Type())
+	}
+	return nil
+}
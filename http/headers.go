@@ -0,0 +1,117 @@
+package http
+
+import (
+	"net/textproto"
+	"strings"
+)
+
+// Headers holds the headers of a request, with canonical names (Eg: "Content-Type"). Lookups are case-insensitive,
+// so `Headers.String("content-type")` works whether the request came through a REST API, which keeps the original
+// casing, or an HTTP API, which lowercases the names.
+type Headers map[string]string
+
+// NewHeaders creates Headers from the given map, canonicalizing the names.
+func NewHeaders(headers map[string]string) Headers {
+	if headers == nil {
+		return nil
+	}
+	h := make(Headers, len(headers))
+	for k, v := range headers {
+		h[CanonicalHeaderKey(k)] = v
+	}
+	return h
+}
+
+// CanonicalHeaderKey returns the canonical format of the header name: the first letter and any letter following a
+// hyphen in upper case, the rest in lower case. Eg: "x-request-id" becomes "X-Request-Id".
+func CanonicalHeaderKey(key string) string {
+	return textproto.CanonicalMIMEHeaderKey(key)
+}
+
+// key returns the key under which the header is stored, falling back to a case-insensitive search for Headers that
+// were not created by NewHeaders.
+func (h Headers) key(key string) string {
+	canonical := CanonicalHeaderKey(key)
+	if _, ok := h[canonical]; ok {
+		return canonical
+	}
+	for k := range h {
+		if strings.EqualFold(k, key) {
+			return k
+		}
+	}
+	return canonical
+}
+
+// Get returns the value of the header, or an empty string when it is not set.
+func (h Headers) Get(key string) string {
+	return h[h.key(key)]
+}
+
+// Set sets the header, replacing any value stored under a different casing.
+func (h Headers) Set(key, value string) {
+	h.Del(key)
+	h[CanonicalHeaderKey(key)] = value
+}
+
+// Del removes the header, whatever its casing.
+func (h Headers) Del(key string) {
+	for k := range h {
+		if strings.EqualFold(k, key) {
+			delete(h, k)
+		}
+	}
+}
+
+func (h Headers) String(key string) (string, bool) {
+	return mapUtils(h).String(h.key(key))
+}
+
+func (h Headers) StringDefault(key, def string) string {
+	return mapUtils(h).StringDefault(h.key(key), def)
+}
+
+func (h Headers) Int(key string) (int, error) {
+	return mapUtils(h).Int(h.key(key))
+}
+
+func (h Headers) IntDefault(key string, def int) int {
+	return mapUtils(h).IntDefault(h.key(key), def)
+}
+
+func (h Headers) Int64(key string) (int64, error) {
+	return mapUtils(h).Int64(h.key(key))
+}
+
+func (h Headers) Int64Default(key string, def int64) int64 {
+	return mapUtils(h).Int64Default(h.key(key), def)
+}
+
+func (h Headers) Float64(key string) (float64, error) {
+	return mapUtils(h).Float64(h.key(key))
+}
+
+func (h Headers) Float64Default(key string, def float64) float64 {
+	return mapUtils(h).Float64Default(h.key(key), def)
+}
+
+func (h Headers) Bool(key string) (bool, error) {
+	return mapUtils(h).Bool(h.key(key))
+}
+
+func (h Headers) BoolDefault(key string, def bool) bool {
+	return mapUtils(h).BoolDefault(h.key(key), def)
+}
+
+// canonicalMultiValues returns a copy of the multi-value headers with canonical names.
+func canonicalMultiValues(headers map[string][]string) MultiValues {
+	if headers == nil {
+		return nil
+	}
+	r := make(MultiValues, len(headers))
+	for k, v := range headers {
+		k = CanonicalHeaderKey(k)
+		r[k] = append(r[k], v...)
+	}
+	return r
+}
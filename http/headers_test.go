@@ -0,0 +1,61 @@
+package http
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewHeaders(t *testing.T) {
+	t.Run("should canonicalize the names", func(t *testing.T) {
+		h := NewHeaders(map[string]string{"content-type": "application/json", "X-REQUEST-ID": "42"})
+		assert.Equal(t, Headers{"Content-Type": "application/json", "X-Request-Id": "42"}, h)
+	})
+
+	t.Run("should return nil for nil headers", func(t *testing.T) {
+		assert.Nil(t, NewHeaders(nil))
+	})
+}
+
+func TestHeaders(t *testing.T) {
+	t.Run("should look up the headers case-insensitively", func(t *testing.T) {
+		h := NewHeaders(map[string]string{"content-length": "42"})
+		v, ok := h.String("Content-Length")
+		require.True(t, ok)
+		assert.Equal(t, "42", v)
+		n, err := h.Int("CONTENT-LENGTH")
+		require.NoError(t, err)
+		assert.Equal(t, 42, n)
+		assert.Equal(t, "42", h.Get("content-length"))
+	})
+
+	t.Run("should look up headers that were not canonicalized", func(t *testing.T) {
+		h := Headers{"x-debug": "true"}
+		assert.True(t, h.BoolDefault("X-Debug", false))
+	})
+
+	t.Run("should replace the value stored under a different casing", func(t *testing.T) {
+		h := Headers{"content-type": "text/plain"}
+		h.Set("CONTENT-TYPE", "application/json")
+		assert.Equal(t, Headers{"Content-Type": "application/json"}, h)
+	})
+
+	t.Run("should delete the header whatever its casing", func(t *testing.T) {
+		h := Headers{"vary": "Origin", "Accept": "*/*"}
+		h.Del("Vary")
+		assert.Equal(t, Headers{"Accept": "*/*"}, h)
+	})
+
+	t.Run("should fail when the header is missing", func(t *testing.T) {
+		_, err := Headers{}.Int("X-Missing")
+		require.ErrorIs(t, err, ErrKeyNotFound)
+	})
+}
+
+func TestCanonicalMultiValues(t *testing.T) {
+	t.Run("should merge the values of names with different casing", func(t *testing.T) {
+		h := canonicalMultiValues(map[string][]string{"accept": {"a"}})
+		assert.Equal(t, MultiValues{"Accept": {"a"}}, h)
+	})
+}
@@ -1,16 +1,26 @@
 package httptest
 
-import "context"
+import (
+	"context"
+	"strings"
+
+	"github.com/jamillosantos/lambda"
+	lambdahttp "github.com/jamillosantos/lambda/http"
+)
 
 type options struct {
-	ctx        context.Context
-	httpMethod string
-	path       string
-	pathParams map[string]string
-	query      map[string]string
-	headers    map[string]string
-	locals     map[string]any
-	req        any
+	ctx          context.Context
+	httpMethod   string
+	path         string
+	pathParams   map[string]string
+	query        map[string]string
+	headers      map[string]string
+	multiQuery   map[string][]string
+	multiHeaders map[string][]string
+	locals       map[string]any
+	req          any
+	resources    []lambdahttp.Resource
+	encoders     map[string]lambdahttp.Encoder
 }
 
 type Option func(*options)
@@ -63,6 +73,22 @@ func WithQueryMap(query map[string]string) Option {
 	}
 }
 
+// WithQueryValues sets every value of a query parameter, as in `?key=v1&key=v2`. Query holds the last one.
+func WithQueryValues(key string, values ...string) Option {
+	return func(o *options) {
+		if o.multiQuery == nil {
+			o.multiQuery = make(map[string][]string)
+		}
+		o.multiQuery[key] = values
+		if o.query == nil {
+			o.query = make(map[string]string)
+		}
+		if len(values) > 0 {
+			o.query[key] = values[len(values)-1]
+		}
+	}
+}
+
 func WithHeader(key, value string) Option {
 	return func(o *options) {
 		o.headers[key] = value
@@ -75,6 +101,23 @@ func WithHeaderMap(headers map[string]string) Option {
 	}
 }
 
+// WithHeaderValues sets every value of a repeated header. Headers holds the last one.
+func WithHeaderValues(key string, values ...string) Option {
+	return func(o *options) {
+		if o.multiHeaders == nil {
+			o.multiHeaders = make(map[string][]string)
+		}
+		o.multiHeaders[key] = values
+		if o.headers == nil {
+			o.headers = make(map[string]string)
+		}
+		if len(values) > 0 {
+			o.headers[key] = values[len(values)-1]
+		}
+	}
+}
+
+// WithLocals sets a local in the Context.
 func WithLocals(key string, value any) Option {
 	return func(o *options) {
 		if o.locals == nil {
@@ -84,8 +127,31 @@ func WithLocals(key string, value any) Option {
 	}
 }
 
+// WithLocal sets the value of a typed key created by lambda.NewKey in the Context.
+func WithLocal[T any](key lambda.Key[T], value T) Option {
+	return WithLocals(key.LocalKey(), value)
+}
+
 func WithRequest[Req any](req Req) Option {
 	return func(o *options) {
 		o.req = req
 	}
 }
+
+// WithResources injects resources, considered as started, in the Context. They are available through
+// lambdahttp.ResourceOf.
+func WithResources(resources ...lambdahttp.Resource) Option {
+	return func(o *options) {
+		o.resources = append(o.resources, resources...)
+	}
+}
+
+// WithEncoder registers an Encoder used by Response.Send for the given media type. See lambdahttp.WithEncoder.
+func WithEncoder(mediaType string, e lambdahttp.Encoder) Option {
+	return func(o *options) {
+		if o.encoders == nil {
+			o.encoders = make(map[string]lambdahttp.Encoder)
+		}
+		o.encoders[strings.ToLower(mediaType)] = e
+	}
+}
@@ -4,7 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
-	"net/http"
+	"strings"
 
 	lambdahttp "github.com/jamillosantos/lambda/http"
 )
@@ -22,6 +22,11 @@ func (t *TestHttpContext[Req, Resp]) ResponseBody() (Resp, error) {
 	return r, nil
 }
 
+// ResponseBytes returns the raw body of the response, including the content of a stream set by Response.Stream.
+func (t *TestHttpContext[Req, Resp]) ResponseBytes() ([]byte, error) {
+	return t.Response.ReadBody()
+}
+
 func Run[Req any, Resp any](handler lambdahttp.Handler[Req, Resp], opts ...Option) (*TestHttpContext[Req, Resp], error) {
 	o := options{
 		ctx:        context.Background(),
@@ -36,22 +41,27 @@ func Run[Req any, Resp any](handler lambdahttp.Handler[Req, Resp], opts ...Optio
 	for _, opt := range opts {
 		opt(&o)
 	}
+	if o.locals == nil {
+		o.locals = make(map[string]any)
+	}
+	if len(o.resources) > 0 {
+		o.ctx = lambdahttp.ContextWithResources(o.ctx, o.resources...)
+	}
 	ctx := &TestHttpContext[Req, Resp]{
 		lambdahttp.Context[Req, Resp]{
 			Context: o.ctx,
 			Request: &lambdahttp.Request[Req]{
-				HTTPMethod: o.httpMethod,
-				Path:       o.path,
-				PathParams: o.pathParams,
-				Query:      lambdahttp.Query(o.query),
-				Headers:    lambdahttp.Headers(o.headers),
-				Body:       o.req.(Req),
+				HTTPMethod:        o.httpMethod,
+				Path:              o.path,
+				PathParams:        o.pathParams,
+				Query:             lambdahttp.Query(o.query),
+				Headers:           lambdahttp.NewHeaders(o.headers),
+				MultiValueQuery:   multiValues(o.query, o.multiQuery),
+				MultiValueHeaders: canonical(multiValues(o.headers, o.multiHeaders)),
+				Body:              o.req.(Req),
 			},
-			Response: &lambdahttp.Response[Resp]{
-				StatusCode: http.StatusOK,
-				Headers:    make(map[string]string),
-			},
-			Locals: o.locals,
+			Response: lambdahttp.NewResponse[Resp](accept(o.headers), o.encoders),
+			Locals:   o.locals,
 		},
 	}
 	err := handler(&ctx.Context)
@@ -64,3 +74,33 @@ func Run[Req any, Resp any](handler lambdahttp.Handler[Req, Resp], opts ...Optio
 	}
 	return ctx, nil
 }
+
+// multiValues builds the multi-value map from the single values, overridden by the multiple values set explicitly.
+func multiValues(single map[string]string, multi map[string][]string) lambdahttp.MultiValues {
+	r := make(lambdahttp.MultiValues, len(single)+len(multi))
+	for k, v := range single {
+		r[k] = []string{v}
+	}
+	for k, v := range multi {
+		r[k] = v
+	}
+	return r
+}
+
+// canonical canonicalizes the header names of the multi-value headers.
+func canonical(headers lambdahttp.MultiValues) lambdahttp.MultiValues {
+	r := make(lambdahttp.MultiValues, len(headers))
+	for k, v := range headers {
+		r[lambdahttp.CanonicalHeaderKey(k)] = v
+	}
+	return r
+}
+
+func accept(headers map[string]string) string {
+	for k, v := range headers {
+		if strings.EqualFold(k, "Accept") {
+			return v
+		}
+	}
+	return ""
+}
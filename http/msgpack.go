@@ -0,0 +1,367 @@
+package http
+
+import (
+	"encoding"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// encodeMsgPack encodes the value as MessagePack. Structs are encoded as maps using the `msgpack` tag, or the `json`
+// tag, or the name of the fields, promoting embedded structs and honouring omitempty like encoding/json. time.Time is
+// encoded with the timestamp extension type.
+func encodeMsgPack(w io.Writer, v any) error {
+	e := msgpackEncoder{}
+	if err := e.encode(reflect.ValueOf(v)); err != nil {
+		return err
+	}
+	_, err := w.Write(e.buf)
+	return err
+}
+
+type msgpackEncoder struct {
+	buf []byte
+}
+
+func (e *msgpackEncoder) encode(v reflect.Value) error {
+	if !v.IsValid() {
+		e.buf = append(e.buf, 0xc0)
+		return nil
+	}
+	if v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			e.buf = append(e.buf, 0xc0)
+			return nil
+		}
+		return e.encode(v.Elem())
+	}
+
+	if v.Type() == timeType {
+		e.encodeTime(v.Interface().(time.Time))
+		return nil
+	}
+	if m, ok := v.Interface().(encoding.TextMarshaler); ok && v.Kind() != reflect.String {
+		b, err := m.MarshalText()
+		if err != nil {
+			return err
+		}
+		e.encodeString(string(b))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			e.buf = append(e.buf, 0xc3)
+		} else {
+			e.buf = append(e.buf, 0xc2)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		e.encodeInt(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		e.encodeUint(v.Uint())
+	case reflect.Float32:
+		e.buf = append(e.buf, 0xca)
+		e.buf = binary.BigEndian.AppendUint32(e.buf, math.Float32bits(float32(v.Float())))
+	case reflect.Float64:
+		e.buf = append(e.buf, 0xcb)
+		e.buf = binary.BigEndian.AppendUint64(e.buf, math.Float64bits(v.Float()))
+	case reflect.String:
+		e.encodeString(v.String())
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			e.buf = append(e.buf, 0xc0)
+			return nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, v.Len())
+			reflect.Copy(reflect.ValueOf(b), v)
+			e.encodeBytes(b)
+			return nil
+		}
+		e.encodeLength(v.Len(), 0x90, 0xdc, 0xdd)
+		for i := 0; i < v.Len(); i++ {
+			if err := e.encode(v.Index(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		if v.IsNil() {
+			e.buf = append(e.buf, 0xc0)
+			return nil
+		}
+		keys := v.MapKeys()
+		// Sorting the keys makes the output deterministic.
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+		})
+		e.encodeLength(len(keys), 0x80, 0xde, 0xdf)
+		for _, k := range keys {
+			if err := e.encode(k); err != nil {
+				return err
+			}
+			if err := e.encode(v.MapIndex(k)); err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		return e.encodeStruct(v)
+	default:
+		return fmt.Errorf("msgpack: unsupported type %s", v.Type())
+	}
+	return nil
+}
+
+func (e *msgpackEncoder) encodeStruct(v reflect.Value) error {
+	fields := msgpackFields(v.Type())
+	values := make([]reflect.Value, 0, len(fields))
+	names := make([]string, 0, len(fields))
+	for _, f := range fields {
+		fv, ok := fieldByIndex(v, f.index)
+		if !ok || (f.omitEmpty && isEmptyValue(fv)) {
+			continue
+		}
+		values = append(values, fv)
+		names = append(names, f.name)
+	}
+	e.encodeLength(len(values), 0x80, 0xde, 0xdf)
+	for i, fv := range values {
+		e.encodeString(names[i])
+		if err := e.encode(fv); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type msgpackField struct {
+	name      string
+	index     []int
+	tagged    bool
+	omitEmpty bool
+}
+
+// msgpackFields returns the fields to encode for the struct type. Like encoding/json, the fields of embedded structs
+// are promoted unless the embedded field is named by a tag, and a shallower or tagged field hides the others with the
+// same name.
+func msgpackFields(t reflect.Type) []msgpackField {
+	var fields []msgpackField
+	collectMsgpackFields(t, nil, &fields)
+
+	byName := make(map[string][]msgpackField, len(fields))
+	var order []string
+	for _, f := range fields {
+		if _, ok := byName[f.name]; !ok {
+			order = append(order, f.name)
+		}
+		byName[f.name] = append(byName[f.name], f)
+	}
+	result := make([]msgpackField, 0, len(order))
+	for _, name := range order {
+		if f, ok := dominantField(byName[name]); ok {
+			result = append(result, f)
+		}
+	}
+	// The promoted fields keep the position of the field that won, as in encoding/json.
+	slices.SortFunc(result, func(a, b msgpackField) int {
+		return slices.Compare(a.index, b.index)
+	})
+	return result
+}
+
+func collectMsgpackFields(t reflect.Type, index []int, fields *[]msgpackField) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, omitEmpty, tagged, ok := msgpackTag(f)
+		if !ok {
+			continue
+		}
+		fieldIndex := append(append([]int(nil), index...), i)
+		if f.Anonymous && !tagged {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				collectMsgpackFields(ft, fieldIndex, fields)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		*fields = append(*fields, msgpackField{name: name, index: fieldIndex, tagged: tagged, omitEmpty: omitEmpty})
+	}
+}
+
+// dominantField returns the field that wins among the fields with the same name: the shallowest one, or the only
+// tagged one at that depth. No field is encoded when it is ambiguous.
+func dominantField(fields []msgpackField) (msgpackField, bool) {
+	depth := len(fields[0].index)
+	for _, f := range fields[1:] {
+		depth = min(depth, len(f.index))
+	}
+	var candidates []msgpackField
+	for _, f := range fields {
+		if len(f.index) == depth {
+			candidates = append(candidates, f)
+		}
+	}
+	if len(candidates) == 1 {
+		return candidates[0], true
+	}
+	var tagged []msgpackField
+	for _, f := range candidates {
+		if f.tagged {
+			tagged = append(tagged, f)
+		}
+	}
+	if len(tagged) == 1 {
+		return tagged[0], true
+	}
+	return msgpackField{}, false
+}
+
+// msgpackTag reads the `msgpack` tag, or the `json` tag, of the field.
+func msgpackTag(f reflect.StructField) (name string, omitEmpty bool, tagged bool, ok bool) {
+	for _, t := range []string{"msgpack", "json"} {
+		v, found := f.Tag.Lookup(t)
+		if !found {
+			continue
+		}
+		name, opts, _ := strings.Cut(v, ",")
+		if name == "-" && opts == "" {
+			return "", false, false, false
+		}
+		omitEmpty = slices.Contains(strings.Split(opts, ","), "omitempty")
+		if name == "" {
+			return f.Name, omitEmpty, false, true
+		}
+		return name, omitEmpty, true, true
+	}
+	return f.Name, false, false, true
+}
+
+// fieldByIndex returns the nested field, or false when an embedded pointer on the way is nil.
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+// isEmptyValue reports whether the value is empty in the sense of the omitempty option of encoding/json.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64,
+		reflect.Interface, reflect.Pointer:
+		return v.IsZero()
+	}
+	return false
+}
+
+func (e *msgpackEncoder) encodeInt(i int64) {
+	switch {
+	case i >= 0:
+		e.encodeUint(uint64(i))
+	case i >= -32:
+		e.buf = append(e.buf, byte(i))
+	case i >= math.MinInt8:
+		e.buf = append(e.buf, 0xd0, byte(i))
+	case i >= math.MinInt16:
+		e.buf = append(e.buf, 0xd1)
+		e.buf = binary.BigEndian.AppendUint16(e.buf, uint16(i))
+	case i >= math.MinInt32:
+		e.buf = append(e.buf, 0xd2)
+		e.buf = binary.BigEndian.AppendUint32(e.buf, uint32(i))
+	default:
+		e.buf = append(e.buf, 0xd3)
+		e.buf = binary.BigEndian.AppendUint64(e.buf, uint64(i))
+	}
+}
+
+func (e *msgpackEncoder) encodeUint(u uint64) {
+	switch {
+	case u <= 0x7f:
+		e.buf = append(e.buf, byte(u))
+	case u <= math.MaxUint8:
+		e.buf = append(e.buf, 0xcc, byte(u))
+	case u <= math.MaxUint16:
+		e.buf = append(e.buf, 0xcd)
+		e.buf = binary.BigEndian.AppendUint16(e.buf, uint16(u))
+	case u <= math.MaxUint32:
+		e.buf = append(e.buf, 0xce)
+		e.buf = binary.BigEndian.AppendUint32(e.buf, uint32(u))
+	default:
+		e.buf = append(e.buf, 0xcf)
+		e.buf = binary.BigEndian.AppendUint64(e.buf, u)
+	}
+}
+
+func (e *msgpackEncoder) encodeString(s string) {
+	switch l := len(s); {
+	case l <= 31:
+		e.buf = append(e.buf, 0xa0|byte(l))
+	case l <= math.MaxUint8:
+		e.buf = append(e.buf, 0xd9, byte(l))
+	case l <= math.MaxUint16:
+		e.buf = append(e.buf, 0xda)
+		e.buf = binary.BigEndian.AppendUint16(e.buf, uint16(l))
+	default:
+		e.buf = append(e.buf, 0xdb)
+		e.buf = binary.BigEndian.AppendUint32(e.buf, uint32(l))
+	}
+	e.buf = append(e.buf, s...)
+}
+
+func (e *msgpackEncoder) encodeBytes(b []byte) {
+	switch l := len(b); {
+	case l <= math.MaxUint8:
+		e.buf = append(e.buf, 0xc4, byte(l))
+	case l <= math.MaxUint16:
+		e.buf = append(e.buf, 0xc5)
+		e.buf = binary.BigEndian.AppendUint16(e.buf, uint16(l))
+	default:
+		e.buf = append(e.buf, 0xc6)
+		e.buf = binary.BigEndian.AppendUint32(e.buf, uint32(l))
+	}
+	e.buf = append(e.buf, b...)
+}
+
+// encodeLength writes the header of arrays and maps: the fix format holds up to 15 elements, then 16 and 32 bits.
+func (e *msgpackEncoder) encodeLength(l int, fix, b16, b32 byte) {
+	switch {
+	case l <= 15:
+		e.buf = append(e.buf, fix|byte(l))
+	case l <= math.MaxUint16:
+		e.buf = append(e.buf, b16)
+		e.buf = binary.BigEndian.AppendUint16(e.buf, uint16(l))
+	default:
+		e.buf = append(e.buf, b32)
+		e.buf = binary.BigEndian.AppendUint32(e.buf, uint32(l))
+	}
+}
+
+// encodeTime writes the timestamp 96 format of the timestamp extension type (-1).
+func (e *msgpackEncoder) encodeTime(t time.Time) {
+	e.buf = append(e.buf, 0xc7, 12, 0xff)
+	e.buf = binary.BigEndian.AppendUint32(e.buf, uint32(t.Nanosecond()))
+	e.buf = binary.BigEndian.AppendUint64(e.buf, uint64(t.Unix()))
+}
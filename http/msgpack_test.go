@@ -0,0 +1,87 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeMsgPack(t *testing.T) {
+	type item struct {
+		ID      int    `msgpack:"id"`
+		Name    string `json:"name"`
+		Ignored string `msgpack:"-"`
+	}
+	tests := []struct {
+		name string
+		v    any
+		want []byte
+	}{
+		{"nil", nil, []byte{0xc0}},
+		{"true", true, []byte{0xc3}},
+		{"positive fixint", 7, []byte{0x07}},
+		{"negative fixint", -1, []byte{0xff}},
+		{"uint8", 200, []byte{0xcc, 0xc8}},
+		{"int16", -200, []byte{0xd1, 0xff, 0x38}},
+		{"uint32", 70000, []byte{0xce, 0x00, 0x01, 0x11, 0x70}},
+		{"float64", 1.5, []byte{0xcb, 0x3f, 0xf8, 0, 0, 0, 0, 0, 0}},
+		{"fixstr", "hi", []byte{0xa2, 'h', 'i'}},
+		{"str8", strings.Repeat("a", 32), append([]byte{0xd9, 32}, strings.Repeat("a", 32)...)},
+		{"bin", []byte{1, 2}, []byte{0xc4, 0x02, 0x01, 0x02}},
+		{"array", []int{1, 2}, []byte{0x92, 0x01, 0x02}},
+		{"struct", item{ID: 1, Name: "a"}, []byte{0x82, 0xa2, 'i', 'd', 0x01, 0xa4, 'n', 'a', 'm', 'e', 0xa1, 'a'}},
+		{"pointer", &item{ID: 1, Name: "a"}, []byte{0x82, 0xa2, 'i', 'd', 0x01, 0xa4, 'n', 'a', 'm', 'e', 0xa1, 'a'}},
+		{"timestamp", time.Unix(1, 2), []byte{0xc7, 12, 0xff, 0, 0, 0, 2, 0, 0, 0, 0, 0, 0, 0, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, encodeMsgPack(&buf, tt.v))
+			assert.Equal(t, tt.want, buf.Bytes())
+		})
+	}
+
+	t.Run("should fail on unsupported types", func(t *testing.T) {
+		var buf bytes.Buffer
+		assert.Error(t, encodeMsgPack(&buf, make(chan int)))
+	})
+
+	t.Run("should encode the same fields as encoding/json", func(t *testing.T) {
+		type base struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		}
+		type audit struct {
+			Kind string `json:"kind"`
+		}
+		type user struct {
+			base
+			*audit
+			Name     string   `json:"name"`
+			Note     string   `json:"note,omitempty"`
+			Tags     []string `json:"tags,omitempty"`
+			Verified bool     `json:"verified,omitempty"`
+		}
+		for _, v := range []user{
+			{base: base{ID: "1", Name: "hidden"}, Name: "John"},
+			{base: base{ID: "1"}, audit: &audit{Kind: "admin"}, Name: "John", Note: "vip", Tags: []string{"a"}, Verified: true},
+		} {
+			b, err := json.Marshal(v)
+			require.NoError(t, err)
+			var fields map[string]any
+			require.NoError(t, json.Unmarshal(b, &fields))
+			// The fields are declared in alphabetical order, the order of the encoded map keys.
+			var want bytes.Buffer
+			require.NoError(t, encodeMsgPack(&want, fields))
+
+			var got bytes.Buffer
+			require.NoError(t, encodeMsgPack(&got, v))
+			assert.Equal(t, want.Bytes(), got.Bytes())
+		}
+	})
+}
@@ -0,0 +1,93 @@
+package http
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MultiValues holds every value of the query parameters or headers of a request.
+//
+// REST APIs (StartV1) send each value separately, while HTTP APIs (StartV2) join repeated values with commas. Values
+// returns them as received, Strings and the typed parsers also split the comma-separated values, so `?tag=a&tag=b`
+// and `?tag=a,b` are read the same way.
+type MultiValues map[string][]string
+
+// Values returns the values of the key, as received.
+func (m MultiValues) Values(key string) []string {
+	return m[key]
+}
+
+// Strings returns the values of the key, splitting the comma-separated ones. Empty values are dropped.
+func (m MultiValues) Strings(key string) []string {
+	values, ok := m[key]
+	if !ok {
+		return nil
+	}
+	return splitValues(values)
+}
+
+// Ints parses the values of the key, as returned by Strings, as ints.
+func (m MultiValues) Ints(key string) ([]int, error) {
+	return parseValues(m, key, strconv.Atoi)
+}
+
+// Int64s parses the values of the key, as returned by Strings, as int64s.
+func (m MultiValues) Int64s(key string) ([]int64, error) {
+	return parseValues(m, key, func(s string) (int64, error) {
+		return strconv.ParseInt(s, 10, 64)
+	})
+}
+
+// Float64s parses the values of the key, as returned by Strings, as float64s.
+func (m MultiValues) Float64s(key string) ([]float64, error) {
+	return parseValues(m, key, func(s string) (float64, error) {
+		return strconv.ParseFloat(s, 64)
+	})
+}
+
+// Bools parses the values of the key, as returned by Strings, as bools.
+func (m MultiValues) Bools(key string) ([]bool, error) {
+	return parseValues(m, key, strconv.ParseBool)
+}
+
+func parseValues[T any](m MultiValues, key string, parse func(string) (T, error)) ([]T, error) {
+	values, ok := m[key]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrKeyNotFound, key)
+	}
+	values = splitValues(values)
+	result := make([]T, len(values))
+	for i, value := range values {
+		v, err := parse(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", key, err)
+		}
+		result[i] = v
+	}
+	return result, nil
+}
+
+func splitValues(values []string) []string {
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		for _, v := range strings.Split(value, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				result = append(result, v)
+			}
+		}
+	}
+	return result
+}
+
+// toMultiValues wraps each value of the map in a slice.
+func toMultiValues(m map[string]string) MultiValues {
+	if m == nil {
+		return nil
+	}
+	r := make(MultiValues, len(m))
+	for k, v := range m {
+		r[k] = []string{v}
+	}
+	return r
+}
@@ -0,0 +1,59 @@
+package http
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultiValues(t *testing.T) {
+	m := MultiValues{
+		"tag":   {"a", "b,c"},
+		"id":    {"1,2", " 3 "},
+		"price": {"1.5"},
+		"flag":  {"true", "false"},
+		"bad":   {"1,x"},
+		"empty": {""},
+	}
+
+	t.Run("should return the values as received", func(t *testing.T) {
+		assert.Equal(t, []string{"a", "b,c"}, m.Values("tag"))
+		assert.Nil(t, m.Values("missing"))
+	})
+
+	t.Run("should split the comma-separated values", func(t *testing.T) {
+		assert.Equal(t, []string{"a", "b", "c"}, m.Strings("tag"))
+		assert.Empty(t, m.Strings("empty"))
+		assert.Nil(t, m.Strings("missing"))
+	})
+
+	t.Run("should parse the values", func(t *testing.T) {
+		ints, err := m.Ints("id")
+		require.NoError(t, err)
+		assert.Equal(t, []int{1, 2, 3}, ints)
+
+		int64s, err := m.Int64s("id")
+		require.NoError(t, err)
+		assert.Equal(t, []int64{1, 2, 3}, int64s)
+
+		floats, err := m.Float64s("price")
+		require.NoError(t, err)
+		assert.Equal(t, []float64{1.5}, floats)
+
+		bools, err := m.Bools("flag")
+		require.NoError(t, err)
+		assert.Equal(t, []bool{true, false}, bools)
+	})
+
+	t.Run("should fail when a value is invalid", func(t *testing.T) {
+		_, err := m.Ints("bad")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to parse bad")
+	})
+
+	t.Run("should fail when the key is missing", func(t *testing.T) {
+		_, err := m.Ints("missing")
+		require.ErrorIs(t, err, ErrKeyNotFound)
+	})
+}
@@ -0,0 +1,92 @@
+package http
+
+import (
+	"strconv"
+	"strings"
+)
+
+type acceptRange struct {
+	typ     string
+	subtype string
+	q       float64
+}
+
+// specificity returns how specific the range is: 2 for type/subtype, 1 for type/* and 0 for */*.
+func (a acceptRange) specificity() int {
+	switch {
+	case a.typ == "*":
+		return 0
+	case a.subtype == "*":
+		return 1
+	}
+	return 2
+}
+
+func (a acceptRange) matches(mediaType string) bool {
+	typ, subtype, _ := strings.Cut(mediaType, "/")
+	return (a.typ == "*" || a.typ == typ) && (a.subtype == "*" || a.subtype == subtype)
+}
+
+// parseAccept parses the Accept header. Invalid ranges are ignored.
+func parseAccept(header string) []acceptRange {
+	parts := strings.Split(header, ",")
+	ranges := make([]acceptRange, 0, len(parts))
+	for _, part := range parts {
+		mediaRange, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		typ, subtype, ok := strings.Cut(strings.ToLower(strings.TrimSpace(mediaRange)), "/")
+		if !ok || typ == "" || subtype == "" || (typ == "*" && subtype != "*") {
+			continue
+		}
+		r := acceptRange{typ: typ, subtype: subtype, q: 1}
+		for _, param := range strings.Split(params, ";") {
+			k, v, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if strings.EqualFold(k, "q") {
+				if q, err := strconv.ParseFloat(v, 64); err == nil && q >= 0 && q <= 1 {
+					r.q = q
+				}
+			}
+		}
+		ranges = append(ranges, r)
+	}
+	return ranges
+}
+
+// negotiate picks, among the available media types, the one preferred by the Accept header. The quality of a media
+// type is the one of the most specific range matching it. Ties are broken by the order of the ranges in the header,
+// and then by the order of the available media types.
+//
+// An empty Accept header accepts the first available media type. It returns false when nothing is acceptable.
+func negotiate(accept string, available []string) (string, bool) {
+	if len(available) == 0 {
+		return "", false
+	}
+	if strings.TrimSpace(accept) == "" {
+		return available[0], true
+	}
+	ranges := parseAccept(accept)
+
+	var (
+		best      string
+		bestQ     float64
+		bestIndex int
+	)
+	for _, mediaType := range available {
+		matchIndex, matchSpecificity := -1, -1
+		for i, r := range ranges {
+			if r.matches(mediaType) && r.specificity() > matchSpecificity {
+				matchIndex, matchSpecificity = i, r.specificity()
+			}
+		}
+		if matchIndex < 0 {
+			continue
+		}
+		q := ranges[matchIndex].q
+		if q <= 0 {
+			continue
+		}
+		if best == "" || q > bestQ || (q == bestQ && matchIndex < bestIndex) {
+			best, bestQ, bestIndex = mediaType, q, matchIndex
+		}
+	}
+	return best, best != ""
+}
@@ -5,6 +5,10 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jamillosantos/lambda/internal/resources"
 )
 
 type Resource interface {
@@ -12,6 +16,18 @@ type Resource interface {
 	Start(context.Context) error
 }
 
+// Stopper is an optional interface implemented by resources that need to be released when the execution environment
+// shuts down. Resources are stopped in the reverse order they were started.
+type Stopper interface {
+	Stop(context.Context) error
+}
+
+// Dependent is an optional interface implemented by resources that can only be started after other resources. The
+// dependencies are referenced by their Name. Resources that do not depend on each other are started concurrently.
+type Dependent interface {
+	DependsOn() []string
+}
+
 type HttpResponse struct {
 	StatusCode int
 	Headers    map[string]string
@@ -19,8 +35,20 @@ type HttpResponse struct {
 }
 
 type options struct {
-	resources    []Resource
-	errorHandler func(context.Context, error) (HttpResponse, error)
+	resources       []Resource
+	errorHandler    func(context.Context, error) (HttpResponse, error)
+	shutdownTimeout time.Duration
+	startObserver   func(name string, duration time.Duration, err error)
+	policies        map[string]resources.Policy
+	validator       func(any) error
+
+	disallowUnknownFields bool
+	disallowTrailingData  bool
+	maxBodySize           int64
+	decoders              map[string]Decoder
+	encoders              map[string]Encoder
+
+	streaming bool
 }
 
 func defaultOpts() options {
@@ -46,17 +74,121 @@ func WithErrorHandler(h func(context.Context, error) (HttpResponse, error)) Http
 	}
 }
 
+// WithShutdownTimeout is an option that sets how long the resources have to stop when the execution environment shuts
+// down. The default is 500ms, which is the time Lambda waits between SIGTERM and SIGKILL.
+func WithShutdownTimeout(d time.Duration) HttpOption {
+	return func(o *options) {
+		o.shutdownTimeout = d
+	}
+}
+
+// WithResourceStartObserver is an option that registers a function called after each resource start attempt with the
+// time it took. It is useful to find out which resource dominates the cold start.
+func WithResourceStartObserver(f func(name string, duration time.Duration, err error)) HttpOption {
+	return func(o *options) {
+		o.startObserver = f
+	}
+}
+
+// WithResourcePolicy is an option that defines the policy used to start the resource with the given name.
+func WithResourcePolicy(name string, policy ResourcePolicy) HttpOption {
+	return func(o *options) {
+		if o.policies == nil {
+			o.policies = make(map[string]resources.Policy)
+		}
+		o.policies[name] = policy
+	}
+}
+
+// WithValidator is an option that registers a validator called with a pointer to the request body after it is decoded
+// (Eg: the Struct method of github.com/go-playground/validator). It runs after the Validate method of the body, if
+// any. Failures are reported as a ValidationError.
+func WithValidator(v func(any) error) HttpOption {
+	return func(o *options) {
+		o.validator = v
+	}
+}
+
+// WithDisallowUnknownFields is an option that makes the request body decoding fail when the body has fields that are
+// not present in the request type.
+func WithDisallowUnknownFields() HttpOption {
+	return func(o *options) {
+		o.disallowUnknownFields = true
+	}
+}
+
+// WithDisallowTrailingData is an option that makes the request body decoding fail when there is data after the
+// decoded value (Eg: `{"name":"John"} {}`).
+func WithDisallowTrailingData() HttpOption {
+	return func(o *options) {
+		o.disallowTrailingData = true
+	}
+}
+
+// WithMaxBodySize is an option that limits the size, in bytes, of the decoded request body. Larger bodies are rejected
+// with a 413 Request Entity Too Large error, whose cause is ErrBodyTooLarge.
+func WithMaxBodySize(n int64) HttpOption {
+	return func(o *options) {
+		o.maxBodySize = n
+	}
+}
+
+// WithDecoder is an option that registers the Decoder used for request bodies of the given media type (Eg:
+// "application/xml"). It replaces the built-in decoder of the media type, if any.
+//
+// The built-in decoders are: application/json (and any "+json" media type), application/x-www-form-urlencoded,
+// multipart/form-data and text/plain. Requests without Content-Type are decoded as JSON.
+func WithDecoder(mediaType string, d Decoder) HttpOption {
+	return func(o *options) {
+		if o.decoders == nil {
+			o.decoders = make(map[string]Decoder)
+		}
+		o.decoders[strings.ToLower(mediaType)] = d
+	}
+}
+
+// WithEncoder is an option that registers the Encoder used by Response.Send for the given media type (Eg:
+// "application/yaml"). It replaces the built-in encoder of the media type, if any.
+//
+// The built-in encoders are: application/json, text/plain, application/msgpack, application/x-msgpack and text/csv.
+// XML is opt-in, with XMLEncoder.
+func WithEncoder(mediaType string, e Encoder) HttpOption {
+	return func(o *options) {
+		if o.encoders == nil {
+			o.encoders = make(map[string]Encoder)
+		}
+		o.encoders[strings.ToLower(mediaType)] = e
+	}
+}
+
+// WithResponseStreaming makes StartFunctionURL stream the response (InvokeWithResponseStream): the status, headers
+// and body written so far are sent to the client on each Response.Flush, so large exports and server-sent events do
+// not need to be buffered. It requires the Function URL to be configured with the RESPONSE_STREAM invoke mode.
+//
+// It is ignored by the other Start functions, which always buffer the response.
+func WithResponseStreaming() HttpOption {
+	return func(o *options) {
+		o.streaming = true
+	}
+}
+
 // Error is a struct that implements ErrorResponse. It represents an error that can be returned by the lambda function.
 type Error struct {
 	StatusCode int
 	Headers    map[string]string
 	Message    string
+	// Err is the underlying cause. It is not sent to the client.
+	Err error
 }
 
 func (h *Error) Error() string {
 	return h.Message
 }
 
+func (h *Error) Unwrap() error {
+	return h.Err
+}
+
 func (h *Error) HttpStatusCode() int {
 	return h.StatusCode
 }
@@ -65,6 +197,15 @@ func (h *Error) HttpHeaders() map[string]string {
 	return h.Headers
 }
 
+// serviceUnavailable returns the 503 Service Unavailable Error used when the resources are not ready.
+func serviceUnavailable(err error) *Error {
+	return &Error{
+		StatusCode: http.StatusServiceUnavailable,
+		Message:    http.StatusText(http.StatusServiceUnavailable),
+		Err:        err,
+	}
+}
+
 type httpErrorBody struct {
 	Message string `json:"message,omitempty"`
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -37,3 +38,59 @@ func (m *mockResource) Name() string {
 func (m *mockResource) Start(ctx context.Context) error {
 	return nil
 }
+
+func TestWithShutdownTimeout(t *testing.T) {
+	o := options{}
+	WithShutdownTimeout(time.Second)(&o)
+	assert.Equal(t, time.Second, o.shutdownTimeout)
+}
+
+func TestWithResourceStartObserver(t *testing.T) {
+	o := options{}
+	f := func(string, time.Duration, error) {}
+	WithResourceStartObserver(f)(&o)
+	assert.Equal(t, fmt.Sprintf("%p", f), fmt.Sprintf("%p", o.startObserver))
+}
+
+func TestWithResourcePolicy(t *testing.T) {
+	o := options{}
+	WithResourcePolicy("db", LazyPolicy())(&o)
+	WithResourcePolicy("broker", RetryPolicy(RetryOptions{MaxAttempts: 5}))(&o)
+	assert.Equal(t, LazyPolicy(), o.policies["db"])
+	assert.Equal(t, 5, o.policies["broker"].Retry.MaxAttempts)
+}
+
+func TestStartManager(t *testing.T) {
+	t.Run("should start the registered resources", func(t *testing.T) {
+		c := defaultOpts()
+		WithResources(&mockResource{"db"})(&c)
+		manager := startManager(&c)
+		assert.True(t, manager.Ready("db"))
+	})
+
+	t.Run("should panic when the resources fail to start", func(t *testing.T) {
+		c := defaultOpts()
+		WithResourcePolicy("cache", LazyPolicy())(&c)
+		assert.Panics(t, func() {
+			startManager(&c)
+		})
+	})
+}
+
+func TestWithDisallowUnknownFields(t *testing.T) {
+	o := options{}
+	WithDisallowUnknownFields()(&o)
+	assert.True(t, o.disallowUnknownFields)
+}
+
+func TestWithDisallowTrailingData(t *testing.T) {
+	o := options{}
+	WithDisallowTrailingData()(&o)
+	assert.True(t, o.disallowTrailingData)
+}
+
+func TestWithMaxBodySize(t *testing.T) {
+	o := options{}
+	WithMaxBodySize(1024)(&o)
+	assert.Equal(t, int64(1024), o.maxBodySize)
+}
@@ -0,0 +1,190 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// ProblemContentType is the media type of the problem details documents (RFC 9457).
+const ProblemContentType = "application/problem+json"
+
+// Problem is an error that implements ErrorResponse as a problem details document (RFC 9457).
+type Problem struct {
+	// Type is a URI reference that identifies the problem type. Defaults to "about:blank".
+	Type string
+	// Title is a short summary of the problem type. Defaults to the status text.
+	Title string
+	// Status is the HTTP status code.
+	Status int
+	// Detail is an explanation specific to this occurrence of the problem.
+	Detail string
+	// Instance is a URI reference that identifies this occurrence of the problem.
+	Instance string
+	// Extensions are additional members of the document. They cannot override the standard members.
+	Extensions map[string]any
+	// Headers are additional headers of the response.
+	Headers map[string]string
+	// Err is the underlying cause. It is not sent to the client.
+	Err error
+}
+
+// NewProblem creates a Problem with the given status and detail.
+func NewProblem(status int, detail string) *Problem {
+	return &Problem{
+		Status: status,
+		Detail: detail,
+	}
+}
+
+func (p *Problem) Error() string {
+	if p.Detail != "" {
+		return p.Detail
+	}
+	return p.title()
+}
+
+func (p *Problem) Unwrap() error {
+	return p.Err
+}
+
+func (p *Problem) HttpStatusCode() int {
+	if p.Status == 0 {
+		return http.StatusInternalServerError
+	}
+	return p.Status
+}
+
+func (p *Problem) HttpHeaders() map[string]string {
+	headers := make(map[string]string, len(p.Headers)+1)
+	for k, v := range p.Headers {
+		headers[k] = v
+	}
+	headers["Content-Type"] = ProblemContentType
+	return headers
+}
+
+func (p *Problem) HttpBody() (json.RawMessage, error) {
+	doc := make(map[string]any, len(p.Extensions)+5)
+	for k, v := range p.Extensions {
+		doc[k] = v
+	}
+	doc["type"] = p.Type
+	if p.Type == "" {
+		doc["type"] = "about:blank"
+	}
+	doc["title"] = p.title()
+	doc["status"] = p.HttpStatusCode()
+	if p.Detail != "" {
+		doc["detail"] = p.Detail
+	} else {
+		delete(doc, "detail")
+	}
+	if p.Instance != "" {
+		doc["instance"] = p.Instance
+	} else {
+		delete(doc, "instance")
+	}
+	return json.Marshal(doc)
+}
+
+func (p *Problem) title() string {
+	if p.Title != "" {
+		return p.Title
+	}
+	return http.StatusText(p.HttpStatusCode())
+}
+
+// ProblemErrorHandler is an error handler, to be used with WithErrorHandler, that renders any error as a problem
+// details document (RFC 9457):
+//
+//   - Problem is rendered as is;
+//   - ValidationError is rendered with its status and the invalid fields in the "errors" extension;
+//   - BindError is rendered as a 400 Bad Request with the invalid parameters in the "errors" extension;
+//   - DecodeError is rendered as a 400 Bad Request with the "field" and "offset" extensions, when known;
+//   - Error is rendered with its status and its message as detail;
+//   - Any other ErrorResponse is rendered with its status and headers, its body is discarded;
+//   - Any other error is rendered as a 500 Internal Server Error without detail, to avoid leaking sensitive
+//     information.
+var ProblemErrorHandler = func(_ context.Context, err error) (HttpResponse, error) {
+	p := ToProblem(err)
+	b, berr := p.HttpBody()
+	if berr != nil {
+		return HttpResponse{}, berr
+	}
+	return HttpResponse{
+		StatusCode: p.HttpStatusCode(),
+		Headers:    p.HttpHeaders(),
+		Body:       b,
+	}, nil
+}
+
+// ToProblem converts an error into a Problem. See ProblemErrorHandler for the conversion rules.
+func ToProblem(err error) *Problem {
+	var problem *Problem
+	if errors.As(err, &problem) {
+		return problem
+	}
+
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		return &Problem{
+			Status:     validationErr.HttpStatusCode(),
+			Detail:     "The request body is invalid.",
+			Extensions: map[string]any{"errors": validationErr.Fields},
+			Err:        err,
+		}
+	}
+
+	var bindErr *BindError
+	if errors.As(err, &bindErr) {
+		return &Problem{
+			Status:     bindErr.HttpStatusCode(),
+			Detail:     "The request parameters are invalid.",
+			Extensions: map[string]any{"errors": bindErr.Params},
+			Err:        err,
+		}
+	}
+
+	var decodeErr *DecodeError
+	if errors.As(err, &decodeErr) {
+		extensions := make(map[string]any, 2)
+		if decodeErr.Field != "" {
+			extensions["field"] = decodeErr.Field
+		}
+		if decodeErr.Offset > 0 {
+			extensions["offset"] = decodeErr.Offset
+		}
+		return &Problem{
+			Status:     decodeErr.HttpStatusCode(),
+			Detail:     "The request body is malformed.",
+			Extensions: extensions,
+			Err:        err,
+		}
+	}
+
+	var httpErr *Error
+	if errors.As(err, &httpErr) {
+		return &Problem{
+			Status:  httpErr.HttpStatusCode(),
+			Detail:  httpErr.Message,
+			Headers: httpErr.HttpHeaders(),
+			Err:     err,
+		}
+	}
+
+	var errResponse ErrorResponse
+	if errors.As(err, &errResponse) {
+		return &Problem{
+			Status:  errResponse.HttpStatusCode(),
+			Headers: errResponse.HttpHeaders(),
+			Err:     err,
+		}
+	}
+
+	return &Problem{
+		Status: http.StatusInternalServerError,
+		Err:    err,
+	}
+}
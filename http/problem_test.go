@@ -0,0 +1,111 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProblem_HttpBody(t *testing.T) {
+	t.Run("should render the standard members", func(t *testing.T) {
+		p := &Problem{
+			Type:     "https://example.com/probs/out-of-credit",
+			Title:    "You do not have enough credit.",
+			Status:   http.StatusForbidden,
+			Detail:   "Your current balance is 30, but that costs 50.",
+			Instance: "/account/12345/msgs/abc",
+		}
+		b, err := p.HttpBody()
+		require.NoError(t, err)
+		assert.JSONEq(t, `{
+			"type": "https://example.com/probs/out-of-credit",
+			"title": "You do not have enough credit.",
+			"status": 403,
+			"detail": "Your current balance is 30, but that costs 50.",
+			"instance": "/account/12345/msgs/abc"
+		}`, string(b))
+	})
+
+	t.Run("should use the defaults", func(t *testing.T) {
+		b, err := NewProblem(http.StatusNotFound, "").HttpBody()
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"type":"about:blank","title":"Not Found","status":404}`, string(b))
+	})
+
+	t.Run("should render the extensions without overriding the standard members", func(t *testing.T) {
+		p := &Problem{
+			Status:     http.StatusForbidden,
+			Extensions: map[string]any{"balance": 30, "status": 200, "detail": "overridden"},
+		}
+		b, err := p.HttpBody()
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"type":"about:blank","title":"Forbidden","status":403,"balance":30}`, string(b))
+	})
+}
+
+func TestProblem_HttpHeaders(t *testing.T) {
+	p := &Problem{Headers: map[string]string{"Retry-After": "10"}}
+	assert.Equal(t, map[string]string{"Retry-After": "10", "Content-Type": ProblemContentType}, p.HttpHeaders())
+}
+
+func TestProblemErrorHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "problem",
+			err:        fmt.Errorf("wrapped: %w", NewProblem(http.StatusConflict, "user already exists")),
+			wantStatus: http.StatusConflict,
+			wantBody:   `{"type":"about:blank","title":"Conflict","status":409,"detail":"user already exists"}`,
+		},
+		{
+			name:       "validation error",
+			err:        NewValidationError(FieldError{Field: "name", Message: "is required"}),
+			wantStatus: http.StatusUnprocessableEntity,
+			wantBody:   `{"type":"about:blank","title":"Unprocessable Entity","status":422,"detail":"The request body is invalid.","errors":[{"field":"name","message":"is required"}]}`,
+		},
+		{
+			name:       "decode error",
+			err:        &DecodeError{Field: "age", Offset: 29, Err: errors.New("json: cannot unmarshal")},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"type":"about:blank","title":"Bad Request","status":400,"detail":"The request body is malformed.","field":"age","offset":29}`,
+		},
+		{
+			name:       "http error",
+			err:        &Error{StatusCode: http.StatusBadRequest, Message: "malformed request body"},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"type":"about:blank","title":"Bad Request","status":400,"detail":"malformed request body"}`,
+		},
+		{
+			name:       "generic error",
+			err:        errors.New("database password is 1234"),
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   `{"type":"about:blank","title":"Internal Server Error","status":500}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := ProblemErrorHandler(context.Background(), tt.err)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			assert.Equal(t, ProblemContentType, resp.Headers["Content-Type"])
+			assert.JSONEq(t, tt.wantBody, string(resp.Body))
+		})
+	}
+}
+
+func TestToProblem_BindError(t *testing.T) {
+	t.Run("should list the invalid parameters", func(t *testing.T) {
+		p := ToProblem(&BindError{Params: []ParamError{{In: "query", Name: "limit", Message: "invalid"}}})
+		assert.Equal(t, http.StatusBadRequest, p.HttpStatusCode())
+		assert.Equal(t, []ParamError{{In: "query", Name: "limit", Message: "invalid"}}, p.Extensions["errors"])
+	})
+}
@@ -2,6 +2,7 @@ package http
 
 import (
 	"fmt"
+	"net/url"
 	"strconv"
 	"strings"
 	"sync"
@@ -9,8 +10,6 @@ import (
 
 type Query = mapUtils
 
-type Headers = mapUtils
-
 type PathParams map[string]string
 
 type Request[T any] struct {
@@ -19,8 +18,18 @@ type Request[T any] struct {
 	PathParams PathParams
 	Query      Query
 	Headers    Headers
-	rawCookies []string
-	Body       T
+	// MultiValueQuery holds every value of the query parameters, while Query holds the last one (REST APIs) or the
+	// comma-joined ones (HTTP APIs).
+	MultiValueQuery MultiValues
+	// MultiValueHeaders holds every value of the headers, while Headers holds the last one (REST APIs) or the
+	// comma-joined ones (HTTP APIs).
+	MultiValueHeaders MultiValues
+	rawCookies        []string
+	Body              T
+
+	// rawBody is the body as received from API Gateway, decoded by Typed.
+	rawBody         string
+	isBase64Encoded bool
 
 	parseCookiesOnce sync.Once
 	cookies          map[string]string
@@ -63,6 +72,21 @@ func (p PathParams) Bool(key string) (bool, error) {
 	return strconv.ParseBool(v)
 }
 
+// QueryValues returns every value of the query parameter, splitting the comma-separated ones.
+func (r *Request[T]) QueryValues(key string) []string {
+	return r.MultiValueQuery.Strings(key)
+}
+
+// HeaderValues returns every value of the header, matching its name case-insensitively and splitting the
+// comma-separated values.
+func (r *Request[T]) HeaderValues(key string) []string {
+	values := multiValueHeader(r.MultiValueHeaders, key)
+	if values == nil {
+		return nil
+	}
+	return splitValues(values)
+}
+
 func (r *Request[T]) Cookie(key string) (string, bool) {
 	r.parseCookiesOnce.Do(func() {
 		r.parseCookies()
@@ -108,3 +132,53 @@ func extractCookieValue(cookie string) (string, bool) {
 	}
 	return cookie, true
 }
+
+// headerValue returns the value of the header ignoring the case of the key. REST APIs keep the case sent by the
+// client while HTTP APIs lowercase the header names.
+func headerValue(headers map[string]string, key string) string {
+	if v, ok := headers[key]; ok {
+		return v
+	}
+	for k, v := range headers {
+		if strings.EqualFold(k, key) {
+			return v
+		}
+	}
+	return ""
+}
+
+// multiValueHeader returns the values of the header, matching its name case-insensitively.
+func multiValueHeader(headers map[string][]string, key string) []string {
+	if v, ok := headers[key]; ok {
+		return v
+	}
+	for k, v := range headers {
+		if strings.EqualFold(k, key) {
+			return v
+		}
+	}
+	return nil
+}
+
+// lastValues returns a map with the last value of each key of the multi-value map.
+func lastValues(m map[string][]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	r := make(map[string]string, len(m))
+	for k, v := range m {
+		if len(v) > 0 {
+			r[k] = v[len(v)-1]
+		}
+	}
+	return r
+}
+
+// unescapePath decodes the percent-encoded path sent by HTTP APIs, ALBs and Function URLs, so path params match the
+// ones of REST APIs, which decode the path. Malformed paths are kept as they are.
+func unescapePath(path string) string {
+	if unescaped, err := url.PathUnescape(path); err == nil {
+		return unescaped
+	}
+	return path
+}
@@ -168,3 +168,19 @@ func TestRequest_parseCookies(t *testing.T) {
 	assert.Contains(t, r.cookies, "key6")
 	assert.Contains(t, r.cookies["key6"], "value6")
 }
+
+func TestRequest_Values(t *testing.T) {
+	req := Request[None]{
+		MultiValueQuery:   MultiValues{"tag": {"a", "b"}},
+		MultiValueHeaders: MultiValues{"x-forwarded-for": {"10.0.0.1, 10.0.0.2"}},
+	}
+
+	t.Run("should return every value of the query parameter", func(t *testing.T) {
+		assert.Equal(t, []string{"a", "b"}, req.QueryValues("tag"))
+	})
+
+	t.Run("should match the header name case-insensitively", func(t *testing.T) {
+		assert.Equal(t, []string{"10.0.0.1", "10.0.0.2"}, req.HeaderValues("X-Forwarded-For"))
+		assert.Nil(t, req.HeaderValues("Accept"))
+	})
+}
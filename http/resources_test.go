@@ -0,0 +1,46 @@
+package http
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResourceOf(t *testing.T) {
+	db := &mockResource{"db"}
+	ctx := &Context[None, None]{
+		Context: ContextWithResources(context.Background(), db),
+	}
+
+	t.Run("should return the resource with the requested type", func(t *testing.T) {
+		r, err := ResourceOf[*mockResource](ctx, "db")
+		require.NoError(t, err)
+		assert.Same(t, db, r)
+		assert.True(t, ctx.ResourceReady("db"))
+	})
+
+	t.Run("should return the resource as an interface", func(t *testing.T) {
+		r, err := ResourceOf[Resource](ctx, "db")
+		require.NoError(t, err)
+		assert.Equal(t, "db", r.Name())
+	})
+
+	t.Run("should fail when the resource was not registered", func(t *testing.T) {
+		_, err := ResourceOf[*mockResource](ctx, "cache")
+		assert.ErrorIs(t, err, ErrUnknownResource)
+		assert.EqualError(t, err, "unknown resource: cache")
+	})
+
+	t.Run("should fail when the resource has another type", func(t *testing.T) {
+		_, err := ResourceOf[Stopper](ctx, "db")
+		assert.ErrorIs(t, err, ErrResourceType)
+		assert.EqualError(t, err, "unexpected resource type: resource db is *http.mockResource, not http.Stopper")
+	})
+
+	t.Run("should fail when the context has no resources", func(t *testing.T) {
+		_, err := ResourceOf[*mockResource](&Context[None, None]{Context: context.Background()}, "db")
+		assert.ErrorIs(t, err, ErrNoResources)
+	})
+}
@@ -3,6 +3,7 @@ package http
 import (
 	"bytes"
 	"encoding/json"
+	"io"
 	"net/http"
 	"strconv"
 	"strings"
@@ -12,10 +13,31 @@ import (
 
 type Response[T any] struct {
 	StatusCode int
-	Headers    map[string]string
+	Headers    Headers
 	Cookies    []Cookie
 	Body       bytes.Buffer
 	Err        error
+
+	accept   string
+	encoders map[string]Encoder
+	binary   bool
+	stream   io.Reader
+	// flush sends the response written so far to the client. It is only set when streaming the response.
+	flush func() error
+}
+
+// NewResponse creates a 200 OK Response for a request with the given Accept header. The encoders are used by Send in
+// addition to the built-in ones (see WithEncoder).
+//
+// It is used by the Start functions and by httptest, there is no need to call it from a handler.
+func NewResponse[T any](accept string, encoders map[string]Encoder) *Response[T] {
+	return &Response[T]{
+		StatusCode: http.StatusOK,
+		Headers:    make(Headers),
+		Cookies:    make([]Cookie, 0),
+		accept:     accept,
+		encoders:   encoders,
+	}
 }
 
 func (r *Response[T]) Redirect(url string, status ...int) *Response[T] {
@@ -24,7 +46,7 @@ func (r *Response[T]) Redirect(url string, status ...int) *Response[T] {
 		st = status[0]
 	}
 	r.StatusCode = st
-	r.Headers["Location"] = url
+	r.setHeader("Location", url)
 	return r
 }
 
@@ -33,16 +55,16 @@ func (r *Response[T]) Status(status int) *Response[T] {
 	return r
 }
 
+// Header sets a header of the response. The name is canonicalized (Eg: "content-type" becomes "Content-Type") and
+// replaces any value set under a different casing.
 func (r *Response[T]) Header(key string, value string) *Response[T] {
-	r.Headers[key] = value
+	r.setHeader(key, value)
 	return r
 }
 
 func (r *Response[T]) JSON(data any) *Response[T] {
-	r.Headers["Content-Type"] = "application/json"
-	if r.Body.Len() > 0 {
-		r.Body.Reset()
-	}
+	r.setHeader("Content-Type", "application/json")
+	r.resetBody()
 	err := json.NewEncoder(&r.Body).Encode(data)
 	if err != nil {
 		r.Err = err
@@ -50,16 +72,61 @@ func (r *Response[T]) JSON(data any) *Response[T] {
 	return r
 }
 
+// Send encodes the data in the format negotiated from the Accept header of the request, among the built-in encoders
+// (JSON, plain text, MessagePack and CSV) and the ones registered with WithEncoder. Requests without Accept header
+// get JSON.
+//
+// When no encoder is acceptable, the response fails with a 406 Not Acceptable Error.
+func (r *Response[T]) Send(data any) *Response[T] {
+	mediaTypes, byMediaType := encoders(r.encoders)
+	mediaType, ok := negotiate(r.accept, mediaTypes)
+	if !ok {
+		r.Err = &Error{
+			StatusCode: http.StatusNotAcceptable,
+			Message:    http.StatusText(http.StatusNotAcceptable),
+		}
+		return r
+	}
+	r.setHeader("Content-Type", contentType(mediaType))
+	r.resetBody()
+	if err := byMediaType[mediaType].Encode(&r.Body, data); err != nil {
+		r.Err = err
+	}
+	return r
+}
+
+// SendString writes the string as is, as text/plain.
 func (r *Response[T]) SendString(data string) *Response[T] {
+	return r.Raw(contentType(MIMETextPlain), []byte(data))
+}
+
+// HTML writes the string as is, as text/html.
+func (r *Response[T]) HTML(data string) *Response[T] {
+	return r.Raw(contentType(MIMETextHTML), []byte(data))
+}
+
+// Raw writes the data as is with the given Content-Type.
+func (r *Response[T]) Raw(contentType string, data []byte) *Response[T] {
+	r.resetBody()
+	r.setHeader("Content-Type", contentType)
+	r.Body.Write(data)
+	return r
+}
+
+// resetBody discards the body set previously, including a stream and the binary flag.
+func (r *Response[T]) resetBody() {
 	if r.Body.Len() > 0 {
 		r.Body.Reset()
 	}
+	r.stream = nil
+	r.binary = false
+}
 
-	err := json.NewEncoder(&r.Body).Encode(data)
-	if err != nil {
-		r.Err = err
+func (r *Response[T]) setHeader(key, value string) {
+	if r.Headers == nil {
+		r.Headers = make(Headers)
 	}
-	return r
+	r.Headers.Set(key, value)
 }
 
 func (r *Response[T]) Error() string {
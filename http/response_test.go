@@ -2,11 +2,13 @@ package http
 
 import (
 	"errors"
+	"io"
 	"net/http"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestResponse_Error(t *testing.T) {
@@ -24,17 +26,41 @@ func TestResponse_Header(t *testing.T) {
 	}
 	l := r.Header("key", "value2")
 	assert.Equal(t, r, l)
-	assert.Equal(t, "value2", r.Headers["key"])
+	assert.Equal(t, Headers{"Key": "value2"}, r.Headers)
+}
+
+func TestResponse_Headers(t *testing.T) {
+	t.Run("should read the headers ignoring the case of the name", func(t *testing.T) {
+		r := NewResponse[None]("", nil).Header("x-request-id", "1")
+		assert.Equal(t, "1", r.Headers.Get("X-Request-Id"))
+		assert.Equal(t, "1", r.Headers.Get("x-request-id"))
+	})
+
+	t.Run("should be assignable to the gateway responses", func(t *testing.T) {
+		r := NewResponse[None]("", nil).Header("x-request-id", "1")
+		resp := APIGatewayProxyResponse{Headers: r.Headers}
+		assert.Equal(t, map[string]string{"X-Request-Id": "1"}, resp.Headers)
+	})
 }
 
 func TestResponse_JSON(t *testing.T) {
-	r := &Response[None]{
-		Headers: Headers{},
-	}
-	l := r.JSON(map[string]any{"a": 1})
-	assert.Equal(t, r, l)
-	assert.Equal(t, "application/json", r.Headers["Content-Type"])
-	assert.Equal(t, "{\"a\":1}\n", r.Body.String())
+	t.Run("should encode the data as JSON", func(t *testing.T) {
+		r := &Response[None]{
+			Headers: Headers{},
+		}
+		l := r.JSON(map[string]any{"a": 1})
+		assert.Equal(t, r, l)
+		assert.Equal(t, "application/json", r.Headers["Content-Type"])
+		assert.Equal(t, "{\"a\":1}\n", r.Body.String())
+	})
+
+	t.Run("should replace the Content-Type set under a different casing", func(t *testing.T) {
+		r := &Response[None]{
+			Headers: Headers{"content-type": "text/plain"},
+		}
+		r.JSON(map[string]any{"a": 1})
+		assert.Equal(t, Headers{"Content-Type": "application/json"}, r.Headers)
+	})
 }
 
 func TestResponse_Redirect(t *testing.T) {
@@ -55,13 +81,105 @@ func TestResponse_Redirect(t *testing.T) {
 		assert.Equal(t, r, l)
 		assert.Equal(t, http.StatusMovedPermanently, r.StatusCode)
 	})
+
+	t.Run("should replace the Location set under a different casing", func(t *testing.T) {
+		r := &Response[None]{
+			Headers: Headers{"location": "http://old.example.com"},
+		}
+		r.Redirect("http://example.com")
+		assert.Equal(t, Headers{"Location": "http://example.com"}, r.Headers)
+	})
 }
 
 func TestResponse_SendString(t *testing.T) {
 	r := &Response[None]{}
 	l := r.SendString("data")
 	assert.Equal(t, r, l)
-	assert.Equal(t, "\"data\"\n", r.Body.String())
+	assert.Equal(t, "data", r.Body.String())
+	assert.Equal(t, "text/plain; charset=utf-8", r.Headers["Content-Type"])
+}
+
+func TestResponse_HTML(t *testing.T) {
+	r := &Response[None]{}
+	l := r.HTML("<h1>Hello</h1>")
+	assert.Equal(t, r, l)
+	assert.Equal(t, "<h1>Hello</h1>", r.Body.String())
+	assert.Equal(t, "text/html; charset=utf-8", r.Headers["Content-Type"])
+}
+
+func TestResponse_Raw(t *testing.T) {
+	r := &Response[None]{}
+	r.Body.WriteString("previous")
+	l := r.Raw("application/octet-stream", []byte{0x00, 0x01})
+	assert.Equal(t, r, l)
+	assert.Equal(t, []byte{0x00, 0x01}, r.Body.Bytes())
+	assert.Equal(t, "application/octet-stream", r.Headers["Content-Type"])
+}
+
+func TestResponse_Send(t *testing.T) {
+	type user struct {
+		Name string `json:"name" xml:"name" csv:"name"`
+		Age  int    `json:"age" xml:"age" csv:"age"`
+	}
+	data := []user{{Name: "John", Age: 30}}
+
+	tests := []struct {
+		name            string
+		accept          string
+		data            any
+		wantContentType string
+		wantBody        string
+	}{
+		{"json by default", "", data, "application/json", `[{"name":"John","age":30}]` + "\n"},
+		{"json", "application/json", data, "application/json", `[{"name":"John","age":30}]` + "\n"},
+		{"any", "*/*", data, "application/json", `[{"name":"John","age":30}]` + "\n"},
+		{"browser", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8", map[string]int{"a": 1}, "application/json", `{"a":1}` + "\n"},
+		{"text", "text/plain", "hello", "text/plain; charset=utf-8", "hello"},
+		{"csv", "text/csv", data, "text/csv; charset=utf-8", "name,age\nJohn,30\n"},
+		{"msgpack", "application/msgpack", map[string]int{"a": 1}, "application/msgpack", "\x81\xa1a\x01"},
+		{"highest quality", "application/json;q=0.5, text/csv", data, "text/csv; charset=utf-8", "name,age\nJohn,30\n"},
+		{"first listed on ties", "text/csv, application/json", data, "text/csv; charset=utf-8", "name,age\nJohn,30\n"},
+		{"most specific range", "text/*;q=0.1, text/csv;q=1, */*;q=0", data, "text/csv; charset=utf-8", "name,age\nJohn,30\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewResponse[None](tt.accept, nil)
+			l := r.Send(tt.data)
+			assert.Equal(t, r, l)
+			require.NoError(t, r.Err)
+			assert.Equal(t, tt.wantContentType, r.Headers["Content-Type"])
+			assert.Equal(t, tt.wantBody, r.Body.String())
+		})
+	}
+
+	t.Run("should fail with 406 when nothing is acceptable", func(t *testing.T) {
+		r := NewResponse[None]("image/png, application/json;q=0", nil)
+		r.Send(data)
+		var httpErr *Error
+		require.ErrorAs(t, r.Err, &httpErr)
+		assert.Equal(t, http.StatusNotAcceptable, httpErr.StatusCode)
+	})
+
+	t.Run("should use the registered encoders", func(t *testing.T) {
+		r := NewResponse[None]("application/yaml", map[string]Encoder{
+			"application/yaml": EncoderFunc(func(w io.Writer, v any) error {
+				_, err := io.WriteString(w, "name: John\n")
+				return err
+			}),
+		})
+		r.Send(data)
+		require.NoError(t, r.Err)
+		assert.Equal(t, "application/yaml", r.Headers["Content-Type"])
+		assert.Equal(t, "name: John\n", r.Body.String())
+	})
+
+	t.Run("should encode XML when XMLEncoder is registered", func(t *testing.T) {
+		r := NewResponse[None]("application/xml", map[string]Encoder{MIMEApplicationXML: XMLEncoder})
+		r.Send(user{Name: "John", Age: 30})
+		require.NoError(t, r.Err)
+		assert.Equal(t, "application/xml", r.Headers["Content-Type"])
+		assert.Equal(t, "<user><name>John</name><age>30</age></user>", r.Body.String())
+	})
 }
 
 func TestResponse_Status(t *testing.T) {
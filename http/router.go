@@ -0,0 +1,327 @@
+package http
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+)
+
+type segmentKind int
+
+const (
+	segmentGreedy segmentKind = iota
+	segmentParam
+	segmentStatic
+)
+
+type segment struct {
+	kind  segmentKind
+	value string
+}
+
+type route[Req any, Resp any] struct {
+	method   string
+	pattern  string
+	segments []segment
+	handler  Handler[Req, Resp]
+	group    *Router[Req, Resp]
+}
+
+// Router dispatches requests to handlers registered by HTTP method and path pattern. It is meant to be used when a
+// single lambda function serves multiple routes (Eg: a `{proxy+}` route on API Gateway).
+//
+// Patterns are made of static segments, named parameters (`{id}`) and, as the last segment, greedy parameters
+// (`{path+}`) that match the remainder of the path. The values of the matched parameters are added to
+// Request.PathParams, unless API Gateway already provided them.
+//
+// Router.Serve is a Handler, so it can be passed to StartV1, StartV2 or wrapped by Use:
+//
+//	r := http.NewRouter[http.None, any]()
+//	r.GET("/users/{id}", getUser)
+//	http.StartV2(r.Serve)
+type Router[Req any, Resp any] struct {
+	parent      *Router[Req, Resp]
+	prefix      string
+	middlewares []Middleware[Req, Resp]
+	routes      *[]*route[Req, Resp]
+}
+
+// NewRouter creates an empty Router.
+func NewRouter[Req any, Resp any]() *Router[Req, Resp] {
+	return &Router[Req, Resp]{
+		routes: &[]*route[Req, Resp]{},
+	}
+}
+
+// Use adds middlewares to the router. Middlewares added to the root router wrap every request, including the ones
+// that end up in a 404 or 405 response. Middlewares added to a group only wrap the routes registered in that group
+// (and its subgroups).
+func (r *Router[Req, Resp]) Use(middlewares ...Middleware[Req, Resp]) *Router[Req, Resp] {
+	r.middlewares = append(r.middlewares, middlewares...)
+	return r
+}
+
+// Group creates a subrouter whose routes are prefixed with the given prefix and wrapped by the given middlewares.
+func (r *Router[Req, Resp]) Group(prefix string, middlewares ...Middleware[Req, Resp]) *Router[Req, Resp] {
+	return &Router[Req, Resp]{
+		parent:      r,
+		prefix:      joinPath(r.prefix, prefix),
+		middlewares: middlewares,
+		routes:      r.routes,
+	}
+}
+
+// Handle registers a handler for the given method and pattern. An empty method, or "*", matches any method.
+//
+// It panics if the pattern is invalid.
+func (r *Router[Req, Resp]) Handle(method, pattern string, handler Handler[Req, Resp], middlewares ...Middleware[Req, Resp]) *Router[Req, Resp] {
+	pattern = joinPath(r.prefix, pattern)
+	segments, err := parsePattern(pattern)
+	if err != nil {
+		panic(err)
+	}
+	if len(middlewares) > 0 {
+		handler = Use(handler, middlewares...)
+	}
+	if method == "" {
+		method = "*"
+	}
+	*r.routes = append(*r.routes, &route[Req, Resp]{
+		method:   strings.ToUpper(method),
+		pattern:  pattern,
+		segments: segments,
+		handler:  handler,
+		group:    r,
+	})
+	return r
+}
+
+// GET registers a handler for GET requests on the given pattern.
+func (r *Router[Req, Resp]) GET(pattern string, handler Handler[Req, Resp], middlewares ...Middleware[Req, Resp]) *Router[Req, Resp] {
+	return r.Handle(http.MethodGet, pattern, handler, middlewares...)
+}
+
+// POST registers a handler for POST requests on the given pattern.
+func (r *Router[Req, Resp]) POST(pattern string, handler Handler[Req, Resp], middlewares ...Middleware[Req, Resp]) *Router[Req, Resp] {
+	return r.Handle(http.MethodPost, pattern, handler, middlewares...)
+}
+
+// PUT registers a handler for PUT requests on the given pattern.
+func (r *Router[Req, Resp]) PUT(pattern string, handler Handler[Req, Resp], middlewares ...Middleware[Req, Resp]) *Router[Req, Resp] {
+	return r.Handle(http.MethodPut, pattern, handler, middlewares...)
+}
+
+// PATCH registers a handler for PATCH requests on the given pattern.
+func (r *Router[Req, Resp]) PATCH(pattern string, handler Handler[Req, Resp], middlewares ...Middleware[Req, Resp]) *Router[Req, Resp] {
+	return r.Handle(http.MethodPatch, pattern, handler, middlewares...)
+}
+
+// DELETE registers a handler for DELETE requests on the given pattern.
+func (r *Router[Req, Resp]) DELETE(pattern string, handler Handler[Req, Resp], middlewares ...Middleware[Req, Resp]) *Router[Req, Resp] {
+	return r.Handle(http.MethodDelete, pattern, handler, middlewares...)
+}
+
+// HEAD registers a handler for HEAD requests on the given pattern.
+func (r *Router[Req, Resp]) HEAD(pattern string, handler Handler[Req, Resp], middlewares ...Middleware[Req, Resp]) *Router[Req, Resp] {
+	return r.Handle(http.MethodHead, pattern, handler, middlewares...)
+}
+
+// OPTIONS registers a handler for OPTIONS requests on the given pattern.
+func (r *Router[Req, Resp]) OPTIONS(pattern string, handler Handler[Req, Resp], middlewares ...Middleware[Req, Resp]) *Router[Req, Resp] {
+	return r.Handle(http.MethodOptions, pattern, handler, middlewares...)
+}
+
+// Any registers a handler for any method on the given pattern.
+func (r *Router[Req, Resp]) Any(pattern string, handler Handler[Req, Resp], middlewares ...Middleware[Req, Resp]) *Router[Req, Resp] {
+	return r.Handle("*", pattern, handler, middlewares...)
+}
+
+// Serve dispatches the request to the matching route.
+//
+// When no route matches the path, the response error is set to a 404 Error. When a route matches the path but not the
+// method, the response error is set to a 405 Error with the Allow header. Both are rendered by the error handler.
+func (r *Router[Req, Resp]) Serve(ctx *Context[Req, Resp]) error {
+	root := r
+	for root.parent != nil {
+		root = root.parent
+	}
+	return Use(root.dispatch, root.middlewares...)(ctx)
+}
+
+func (r *Router[Req, Resp]) dispatch(ctx *Context[Req, Resp]) error {
+	path := splitPath(ctx.Request.Path)
+
+	var (
+		best       *route[Req, Resp]
+		bestParams map[string]string
+		allowed    []string
+	)
+	for _, rt := range *r.routes {
+		params, ok := rt.match(path)
+		if !ok {
+			continue
+		}
+		if rt.method != "*" && rt.method != ctx.Request.HTTPMethod {
+			allowed = append(allowed, rt.method)
+			continue
+		}
+		if best == nil || rt.moreSpecificThan(best) {
+			best, bestParams = rt, params
+		}
+	}
+
+	if best == nil {
+		if len(allowed) > 0 {
+			ctx.Response.Err = &Error{
+				StatusCode: http.StatusMethodNotAllowed,
+				Headers:    map[string]string{"Allow": joinMethods(allowed)},
+				Message:    http.StatusText(http.StatusMethodNotAllowed),
+			}
+			return ctx.Response
+		}
+		ctx.Response.Err = &Error{
+			StatusCode: http.StatusNotFound,
+			Message:    http.StatusText(http.StatusNotFound),
+		}
+		return ctx.Response
+	}
+
+	if len(bestParams) > 0 {
+		if ctx.Request.PathParams == nil {
+			ctx.Request.PathParams = make(PathParams, len(bestParams))
+		}
+		for k, v := range bestParams {
+			if _, ok := ctx.Request.PathParams[k]; !ok {
+				ctx.Request.PathParams[k] = v
+			}
+		}
+	}
+
+	return Use(best.handler, best.groupMiddlewares()...)(ctx)
+}
+
+// groupMiddlewares returns the middlewares of the groups the route was registered in, from the outermost to the
+// innermost. The root router middlewares are not included, they are applied by Serve.
+func (rt *route[Req, Resp]) groupMiddlewares() []Middleware[Req, Resp] {
+	var groups []*Router[Req, Resp]
+	for g := rt.group; g.parent != nil; g = g.parent {
+		groups = append(groups, g)
+	}
+	var middlewares []Middleware[Req, Resp]
+	for i := len(groups) - 1; i >= 0; i-- {
+		middlewares = append(middlewares, groups[i].middlewares...)
+	}
+	return middlewares
+}
+
+func (rt *route[Req, Resp]) match(path []string) (map[string]string, bool) {
+	var params map[string]string
+	for i, seg := range rt.segments {
+		if seg.kind == segmentGreedy {
+			if i >= len(path) {
+				return nil, false
+			}
+			if params == nil {
+				params = make(map[string]string)
+			}
+			params[seg.value] = strings.Join(path[i:], "/")
+			return params, true
+		}
+		if i >= len(path) {
+			return nil, false
+		}
+		switch seg.kind {
+		case segmentStatic:
+			if seg.value != path[i] {
+				return nil, false
+			}
+		case segmentParam:
+			if params == nil {
+				params = make(map[string]string)
+			}
+			params[seg.value] = path[i]
+		}
+	}
+	if len(rt.segments) != len(path) {
+		return nil, false
+	}
+	return params, true
+}
+
+// moreSpecificThan reports whether rt should be preferred over other when both match the same path. Static segments
+// are preferred over parameters, and parameters over greedy parameters.
+func (rt *route[Req, Resp]) moreSpecificThan(other *route[Req, Resp]) bool {
+	for i := 0; i < len(rt.segments) && i < len(other.segments); i++ {
+		if rt.segments[i].kind != other.segments[i].kind {
+			return rt.segments[i].kind > other.segments[i].kind
+		}
+	}
+	if len(rt.segments) != len(other.segments) {
+		return len(rt.segments) > len(other.segments)
+	}
+	// An explicit method is more specific than "*".
+	return rt.method != "*" && other.method == "*"
+}
+
+func parsePattern(pattern string) ([]segment, error) {
+	parts := splitPath(pattern)
+	segments := make([]segment, len(parts))
+	for i, part := range parts {
+		if !strings.HasPrefix(part, "{") || !strings.HasSuffix(part, "}") {
+			if strings.ContainsAny(part, "{}") {
+				return nil, &patternError{pattern, "invalid segment " + part}
+			}
+			segments[i] = segment{kind: segmentStatic, value: part}
+			continue
+		}
+		name := part[1 : len(part)-1]
+		kind := segmentParam
+		if strings.HasSuffix(name, "+") {
+			if i != len(parts)-1 {
+				return nil, &patternError{pattern, "greedy parameter " + part + " must be the last segment"}
+			}
+			name = strings.TrimSuffix(name, "+")
+			kind = segmentGreedy
+		}
+		if name == "" {
+			return nil, &patternError{pattern, "empty parameter name"}
+		}
+		segments[i] = segment{kind: kind, value: name}
+	}
+	return segments, nil
+}
+
+type patternError struct {
+	pattern string
+	reason  string
+}
+
+func (e *patternError) Error() string {
+	return "invalid route pattern " + e.pattern + ": " + e.reason
+}
+
+func splitPath(path string) []string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, "/")
+}
+
+func joinPath(prefix, path string) string {
+	return "/" + strings.Trim(strings.TrimRight(prefix, "/")+"/"+strings.TrimLeft(path, "/"), "/")
+}
+
+func joinMethods(methods []string) string {
+	seen := make(map[string]struct{}, len(methods))
+	unique := make([]string, 0, len(methods))
+	for _, m := range methods {
+		if _, ok := seen[m]; ok {
+			continue
+		}
+		seen[m] = struct{}{}
+		unique = append(unique, m)
+	}
+	sort.Strings(unique)
+	return strings.Join(unique, ", ")
+}
@@ -0,0 +1,148 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRouterContext(method, path string) *Context[None, None] {
+	return &Context[None, None]{
+		Context: context.Background(),
+		Request: &Request[None]{
+			HTTPMethod: method,
+			Path:       path,
+		},
+		Response: &Response[None]{
+			StatusCode: http.StatusOK,
+			Headers:    make(map[string]string),
+		},
+		Locals: make(map[string]any),
+	}
+}
+
+func namedHandler(name string) Handler[None, None] {
+	return func(ctx *Context[None, None]) error {
+		ctx.SetLocal("handler", name)
+		return nil
+	}
+}
+
+func TestRouter_Serve(t *testing.T) {
+	r := NewRouter[None, None]()
+	r.GET("/users", namedHandler("list"))
+	r.POST("/users", namedHandler("create"))
+	r.GET("/users/{id}", namedHandler("get"))
+	r.GET("/users/me", namedHandler("me"))
+	r.GET("/files/{path+}", namedHandler("files"))
+
+	t.Run("should dispatch to the handler matching method and path", func(t *testing.T) {
+		ctx := newRouterContext(http.MethodPost, "/users/")
+		require.NoError(t, r.Serve(ctx))
+		assert.Equal(t, "create", ctx.Locals["handler"])
+	})
+
+	t.Run("should fill the path params", func(t *testing.T) {
+		ctx := newRouterContext(http.MethodGet, "/users/42")
+		require.NoError(t, r.Serve(ctx))
+		assert.Equal(t, "get", ctx.Locals["handler"])
+		id, err := ctx.Request.PathParams.Int("id")
+		require.NoError(t, err)
+		assert.Equal(t, 42, id)
+	})
+
+	t.Run("should not override the path params provided by the gateway", func(t *testing.T) {
+		ctx := newRouterContext(http.MethodGet, "/users/42")
+		ctx.Request.PathParams = PathParams{"id": "from-gateway"}
+		require.NoError(t, r.Serve(ctx))
+		assert.Equal(t, "from-gateway", ctx.Request.PathParams["id"])
+	})
+
+	t.Run("should prefer static segments over parameters", func(t *testing.T) {
+		ctx := newRouterContext(http.MethodGet, "/users/me")
+		require.NoError(t, r.Serve(ctx))
+		assert.Equal(t, "me", ctx.Locals["handler"])
+	})
+
+	t.Run("should fill the path params decoded from an escaped path", func(t *testing.T) {
+		ctx := newRouterContext(http.MethodGet, unescapePath("/users/a%20b"))
+		require.NoError(t, r.Serve(ctx))
+		assert.Equal(t, "get", ctx.Locals["handler"])
+		assert.Equal(t, "a b", ctx.Request.PathParams["id"])
+	})
+
+	t.Run("should match the remainder of the path with greedy parameters", func(t *testing.T) {
+		ctx := newRouterContext(http.MethodGet, "/files/a/b/c.txt")
+		require.NoError(t, r.Serve(ctx))
+		assert.Equal(t, "files", ctx.Locals["handler"])
+		assert.Equal(t, "a/b/c.txt", ctx.Request.PathParams["path"])
+	})
+
+	t.Run("should respond 404 when no route matches the path", func(t *testing.T) {
+		ctx := newRouterContext(http.MethodGet, "/orders")
+		err := r.Serve(ctx)
+		require.ErrorIs(t, err, ctx.Response)
+		resp, err := DefaultErrorHandler(ctx.Context, ctx.Response.Err)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("should respond 405 when the path matches but the method does not", func(t *testing.T) {
+		ctx := newRouterContext(http.MethodDelete, "/users")
+		err := r.Serve(ctx)
+		require.ErrorIs(t, err, ctx.Response)
+		resp, err := DefaultErrorHandler(ctx.Context, ctx.Response.Err)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+		assert.Equal(t, "GET, POST", resp.Headers["Allow"])
+	})
+}
+
+func TestRouter_Group(t *testing.T) {
+	seq, m := mBuilder()
+	r := NewRouter[None, None]().Use(m("root"))
+	r.GET("/health", namedHandler("health"))
+	private := r.Group("/private", m("auth"))
+	private.GET("/profile", namedHandler("profile"))
+	private.Group("/admin", m("admin")).GET("/stats", namedHandler("stats"))
+
+	t.Run("should not wrap public routes with the group middlewares", func(t *testing.T) {
+		*seq = nil
+		ctx := newRouterContext(http.MethodGet, "/health")
+		require.NoError(t, r.Serve(ctx))
+		assert.Equal(t, []string{"root-before", "root-after"}, *seq)
+	})
+
+	t.Run("should wrap the group routes with the group middlewares", func(t *testing.T) {
+		*seq = nil
+		ctx := newRouterContext(http.MethodGet, "/private/profile")
+		require.NoError(t, r.Serve(ctx))
+		assert.Equal(t, "profile", ctx.Locals["handler"])
+		assert.Equal(t, []string{"root-before", "auth-before", "auth-after", "root-after"}, *seq)
+	})
+
+	t.Run("should apply the middlewares of nested groups from the outermost", func(t *testing.T) {
+		*seq = nil
+		ctx := newRouterContext(http.MethodGet, "/private/admin/stats")
+		require.NoError(t, r.Serve(ctx))
+		assert.Equal(t, "stats", ctx.Locals["handler"])
+		assert.Equal(t, []string{"root-before", "auth-before", "admin-before", "admin-after", "auth-after", "root-after"}, *seq)
+	})
+}
+
+func TestRouter_Handle(t *testing.T) {
+	t.Run("should panic when the greedy parameter is not the last segment", func(t *testing.T) {
+		assert.Panics(t, func() {
+			NewRouter[None, None]().GET("/{path+}/edit", namedHandler("invalid"))
+		})
+	})
+
+	t.Run("should panic when the parameter name is empty", func(t *testing.T) {
+		assert.Panics(t, func() {
+			NewRouter[None, None]().GET("/users/{}", namedHandler("invalid"))
+		})
+	})
+}
@@ -0,0 +1,159 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+
+	"github.com/jamillosantos/lambda/internal/resources"
+)
+
+// StartALB will start the lambda function, as the target of an Application Load Balancer, with the given handler and
+// options.
+//
+// Both header modes of the target group are supported: when multi-value headers are enabled, Request.MultiValueQuery
+// and Request.MultiValueHeaders hold every value and the response is sent with multi-value headers, one Set-Cookie per
+// cookie. Otherwise, only the last cookie set in the response is sent, as ALB takes a single value per header.
+func StartALB[Req any, Resp any](handler Handler[Req, Resp], opts ...HttpOption) {
+	c := defaultOpts()
+	for _, o := range opts {
+		o(&c)
+	}
+
+	manager := startManager(&c)
+
+	lambda.StartWithOptions(albHandler(handler, &c, manager), manager.LambdaOptions()...)
+}
+
+func albHandler[Req any, Resp any](handler Handler[Req, Resp], c *options, manager *resources.Manager) func(context.Context, events.ALBTargetGroupRequest) (events.ALBTargetGroupResponse, error) {
+	return func(ctx context.Context, albReq events.ALBTargetGroupRequest) (events.ALBTargetGroupResponse, error) {
+		mode := albHeaderMode(albReq.MultiValueHeaders != nil)
+		if err := manager.CheckReady(); err != nil {
+			return mode.toResponse(c.errorHandler(ctx, serviceUnavailable(err)))
+		}
+
+		// ALB does not decode the query string.
+		multiQuery := unescapeQuery(albReq.MultiValueQueryStringParameters)
+		if multiQuery == nil {
+			multiQuery = unescapeQuery(toMultiValues(albReq.QueryStringParameters))
+		}
+		multiHeaders := canonicalMultiValues(albReq.MultiValueHeaders)
+		headers := NewHeaders(albReq.Headers)
+		if mode {
+			headers = NewHeaders(lastValues(multiHeaders))
+		} else {
+			multiHeaders = toMultiValues(headers)
+		}
+
+		req := Request[Req]{
+			HTTPMethod:        albReq.HTTPMethod,
+			Path:              unescapePath(albReq.Path),
+			Query:             Query(lastValues(multiQuery)),
+			Headers:           headers,
+			MultiValueQuery:   multiQuery,
+			MultiValueHeaders: multiHeaders,
+			rawCookies:        multiHeaders["Cookie"],
+			rawBody:           albReq.Body,
+			isBase64Encoded:   albReq.IsBase64Encoded,
+		}
+		resp := NewResponse[Resp](headers.Get("Accept"), c.encoders)
+
+		lambdaContext := Context[Req, Resp]{
+			Context:  resources.NewContext(ctx, manager),
+			Request:  &req,
+			Response: resp,
+			Locals:   make(map[string]any),
+			opts:     c,
+		}
+
+		err := populateBody(albReq.HTTPMethod, albReq.Body, albReq.IsBase64Encoded, headers, &lambdaContext, c)
+		if err != nil {
+			return mode.toResponse(c.errorHandler(ctx, err))
+		}
+
+		err = handler(&lambdaContext)
+		if !(errors.Is(err, lambdaContext.Response)) && err != nil {
+			lambdaContext.error = err
+		}
+		if lambdaContext.Response.Err != nil {
+			return mode.toResponse(c.errorHandler(ctx, lambdaContext.Response.Err))
+		}
+
+		body, isBase64Encoded, err := lambdaContext.Response.GatewayBody()
+		if err != nil {
+			return mode.toResponse(c.errorHandler(ctx, err))
+		}
+
+		r := events.ALBTargetGroupResponse{
+			StatusCode:        lambdaContext.Response.StatusCode,
+			StatusDescription: statusDescription(lambdaContext.Response.StatusCode),
+			Body:              body,
+			IsBase64Encoded:   isBase64Encoded,
+		}
+		cookies := toCookieString(lambdaContext.Response.Cookies)
+		if mode {
+			r.MultiValueHeaders = toMultiValues(lambdaContext.Response.Headers)
+			if len(cookies) > 0 {
+				r.MultiValueHeaders["Set-Cookie"] = cookies
+			}
+		} else {
+			if len(cookies) > 0 {
+				lambdaContext.Response.setHeader("Set-Cookie", cookies[len(cookies)-1])
+			}
+			r.Headers = lambdaContext.Response.Headers
+		}
+		return r, nil
+	}
+}
+
+// albHeaderMode is true when the target group has multi-value headers enabled.
+type albHeaderMode bool
+
+// toResponse converts the response of the error handler, in the header mode of the target group.
+func (m albHeaderMode) toResponse(response HttpResponse, err error) (events.ALBTargetGroupResponse, error) {
+	if err != nil {
+		return events.ALBTargetGroupResponse{}, err
+	}
+	r := events.ALBTargetGroupResponse{
+		StatusCode:        response.StatusCode,
+		StatusDescription: statusDescription(response.StatusCode),
+		Body:              string(response.Body),
+	}
+	if m {
+		r.MultiValueHeaders = toMultiValues(response.Headers)
+	} else {
+		r.Headers = response.Headers
+	}
+	return r, nil
+}
+
+// statusDescription returns the status line ALB expects, Eg: "200 OK".
+func statusDescription(status int) string {
+	return strconv.Itoa(status) + " " + http.StatusText(status)
+}
+
+// unescapeQuery decodes the names and values of the query parameters. Those that are not properly escaped are kept
+// as is.
+func unescapeQuery(query map[string][]string) MultiValues {
+	if query == nil {
+		return nil
+	}
+	r := make(MultiValues, len(query))
+	for k, values := range query {
+		if unescaped, err := url.QueryUnescape(k); err == nil {
+			k = unescaped
+		}
+		for _, v := range values {
+			if unescaped, err := url.QueryUnescape(v); err == nil {
+				v = unescaped
+			}
+			r[k] = append(r[k], v)
+		}
+	}
+	return r
+}
@@ -0,0 +1,109 @@
+package http
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jamillosantos/lambda/internal/resources"
+)
+
+type albUser struct {
+	Name string `json:"name"`
+}
+
+func newALBHandler[Req any, Resp any](t *testing.T, handler Handler[Req, Resp], opts ...HttpOption) func(context.Context, events.ALBTargetGroupRequest) (events.ALBTargetGroupResponse, error) {
+	t.Helper()
+	c := defaultOpts()
+	for _, o := range opts {
+		o(&c)
+	}
+	return albHandler(handler, &c, resources.NewStartedManager(nil))
+}
+
+func TestStartALB(t *testing.T) {
+	t.Run("should handle single-value requests", func(t *testing.T) {
+		h := newALBHandler(t, func(ctx *Context[albUser, map[string]string]) error {
+			session, _ := ctx.Request.Cookie("session")
+			return ctx.Response.SetCookie(Cookie{Name: "a", Value: "1"}).JSON(map[string]string{
+				"name":    ctx.Request.Body.Name,
+				"q":       ctx.Request.Query.StringDefault("q", ""),
+				"tenant":  ctx.Request.Headers.Get("X-Tenant"),
+				"session": session,
+			})
+		})
+
+		resp, err := h(context.Background(), events.ALBTargetGroupRequest{
+			HTTPMethod:            http.MethodPost,
+			Path:                  "/users",
+			QueryStringParameters: map[string]string{"q": "hello%20world"},
+			Headers:               map[string]string{"x-tenant": "acme", "cookie": "session=abc", "content-type": "application/json"},
+			Body:                  base64.StdEncoding.EncodeToString([]byte(`{"name":"John"}`)),
+			IsBase64Encoded:       true,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "200 OK", resp.StatusDescription)
+		assert.Nil(t, resp.MultiValueHeaders)
+		assert.Equal(t, "a=1", resp.Headers["Set-Cookie"])
+		assert.False(t, resp.IsBase64Encoded)
+		assert.JSONEq(t, `{"name":"John","q":"hello world","tenant":"acme","session":"abc"}`, resp.Body)
+	})
+
+	t.Run("should handle multi-value requests", func(t *testing.T) {
+		h := newALBHandler(t, func(ctx *Context[None, None]) error {
+			return ctx.Response.
+				SetCookie(Cookie{Name: "a", Value: "1"}).
+				SetCookie(Cookie{Name: "b", Value: "2"}).
+				SendString(ctx.Request.QueryValues("tag")[1] + " " + ctx.Request.HeaderValues("Accept-Language")[1])
+		})
+
+		resp, err := h(context.Background(), events.ALBTargetGroupRequest{
+			HTTPMethod:                      http.MethodGet,
+			Path:                            "/",
+			MultiValueQueryStringParameters: map[string][]string{"tag": {"a", "b%2Bc"}},
+			MultiValueHeaders:               map[string][]string{"accept-language": {"en", "pt"}},
+		})
+		require.NoError(t, err)
+		assert.Nil(t, resp.Headers)
+		assert.Equal(t, []string{"a=1", "b=2"}, resp.MultiValueHeaders["Set-Cookie"])
+		assert.Equal(t, []string{"text/plain; charset=utf-8"}, resp.MultiValueHeaders["Content-Type"])
+		assert.Equal(t, "b+c pt", resp.Body)
+	})
+
+	t.Run("should render errors with the error handler", func(t *testing.T) {
+		h := newALBHandler(t, func(ctx *Context[albUser, None]) error {
+			return ctx.Response
+		})
+
+		resp, err := h(context.Background(), events.ALBTargetGroupRequest{
+			HTTPMethod:        http.MethodPost,
+			Path:              "/",
+			MultiValueHeaders: map[string][]string{},
+			Body:              `{"name":`,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Equal(t, "400 Bad Request", resp.StatusDescription)
+	})
+	t.Run("should decode the percent-encoded path", func(t *testing.T) {
+		r := NewRouter[None, None]()
+		r.GET("/files/{name}", func(ctx *Context[None, None]) error {
+			return ctx.Response.SendString(ctx.Request.PathParams["name"])
+		})
+		h := newALBHandler(t, r.Serve)
+
+		resp, err := h(context.Background(), events.ALBTargetGroupRequest{
+			HTTPMethod: http.MethodGet,
+			Path:       "/files/a%20b.txt",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "a b.txt", resp.Body)
+	})
+}
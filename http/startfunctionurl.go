@@ -0,0 +1,162 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"errors"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+
+	"github.com/jamillosantos/lambda/internal/resources"
+)
+
+// StartFunctionURL will start the lambda function, invoked through a Lambda Function URL, with the given handler and
+// options.
+//
+// By default the response is buffered, as in StartV2. With WithResponseStreaming, the response is streamed: see
+// Response.Flush.
+func StartFunctionURL[Req any, Resp any](handler Handler[Req, Resp], opts ...HttpOption) {
+	c := defaultOpts()
+	for _, o := range opts {
+		o(&c)
+	}
+
+	manager := startManager(&c)
+
+	if c.streaming {
+		lambda.StartWithOptions(functionURLStreamingHandler(handler, &c, manager), manager.LambdaOptions()...)
+		return
+	}
+	lambda.StartWithOptions(functionURLHandler(handler, &c, manager), manager.LambdaOptions()...)
+}
+
+func functionURLHandler[Req any, Resp any](handler Handler[Req, Resp], c *options, manager *resources.Manager) func(context.Context, events.LambdaFunctionURLRequest) (events.LambdaFunctionURLResponse, error) {
+	return func(ctx context.Context, urlReq events.LambdaFunctionURLRequest) (events.LambdaFunctionURLResponse, error) {
+		if err := manager.CheckReady(); err != nil {
+			return toFunctionURLResponse(c.errorHandler(ctx, serviceUnavailable(err)))
+		}
+
+		lambdaContext := newFunctionURLContext[Req, Resp](ctx, &urlReq, c, manager)
+		err := populateBody(urlReq.RequestContext.HTTP.Method, urlReq.Body, urlReq.IsBase64Encoded, lambdaContext.Request.Headers, lambdaContext, c)
+		if err != nil {
+			return toFunctionURLResponse(c.errorHandler(ctx, err))
+		}
+
+		err = handler(lambdaContext)
+		if !(errors.Is(err, lambdaContext.Response)) && err != nil {
+			lambdaContext.error = err
+		}
+		if lambdaContext.Response.Err != nil {
+			return toFunctionURLResponse(c.errorHandler(ctx, lambdaContext.Response.Err))
+		}
+
+		body, isBase64Encoded, err := lambdaContext.Response.GatewayBody()
+		if err != nil {
+			return toFunctionURLResponse(c.errorHandler(ctx, err))
+		}
+
+		return events.LambdaFunctionURLResponse{
+			StatusCode:      lambdaContext.Response.StatusCode,
+			Headers:         lambdaContext.Response.Headers,
+			Cookies:         toCookieString(lambdaContext.Response.Cookies),
+			Body:            body,
+			IsBase64Encoded: isBase64Encoded,
+		}, nil
+	}
+}
+
+func functionURLStreamingHandler[Req any, Resp any](handler Handler[Req, Resp], c *options, manager *resources.Manager) func(context.Context, events.LambdaFunctionURLRequest) (*events.LambdaFunctionURLStreamingResponse, error) {
+	return func(ctx context.Context, urlReq events.LambdaFunctionURLRequest) (*events.LambdaFunctionURLStreamingResponse, error) {
+		if err := manager.CheckReady(); err != nil {
+			return toFunctionURLStreamingResponse(c.errorHandler(ctx, serviceUnavailable(err)))
+		}
+
+		lambdaContext := newFunctionURLContext[Req, Resp](ctx, &urlReq, c, manager)
+		err := populateBody(urlReq.RequestContext.HTTP.Method, urlReq.Body, urlReq.IsBase64Encoded, lambdaContext.Request.Headers, lambdaContext, c)
+		if err != nil {
+			return toFunctionURLStreamingResponse(c.errorHandler(ctx, err))
+		}
+
+		streamer := newResponseStreamer()
+		attachStreamer(streamer, lambdaContext.Response)
+		return streamer.run(func() error {
+			err := handler(lambdaContext)
+			if !(errors.Is(err, lambdaContext.Response)) && err != nil {
+				lambdaContext.error = err
+			}
+			if lambdaContext.Response.Err != nil {
+				return lambdaContext.Response.Err
+			}
+			if streamer.isStarted() {
+				return lambdaContext.Response.Flush()
+			}
+			return nil
+		}, func(err error) (*events.LambdaFunctionURLStreamingResponse, error) {
+			if err != nil {
+				return toFunctionURLStreamingResponse(c.errorHandler(ctx, err))
+			}
+			if stream := lambdaContext.Response.stream; stream != nil {
+				return &events.LambdaFunctionURLStreamingResponse{
+					StatusCode: lambdaContext.Response.StatusCode,
+					Headers:    lambdaContext.Response.Headers,
+					Cookies:    toCookieString(lambdaContext.Response.Cookies),
+					Body:       stream,
+				}, nil
+			}
+			body, err := lambdaContext.Response.ReadBody()
+			if err != nil {
+				return toFunctionURLStreamingResponse(c.errorHandler(ctx, err))
+			}
+			return &events.LambdaFunctionURLStreamingResponse{
+				StatusCode: lambdaContext.Response.StatusCode,
+				Headers:    lambdaContext.Response.Headers,
+				Cookies:    toCookieString(lambdaContext.Response.Cookies),
+				Body:       bytes.NewReader(body),
+			}, nil
+		})
+	}
+}
+
+func newFunctionURLContext[Req any, Resp any](ctx context.Context, urlReq *events.LambdaFunctionURLRequest, c *options, manager *resources.Manager) *Context[Req, Resp] {
+	headers := NewHeaders(urlReq.Headers)
+	return &Context[Req, Resp]{
+		Context: resources.NewContext(ctx, manager),
+		Request: &Request[Req]{
+			HTTPMethod:        urlReq.RequestContext.HTTP.Method,
+			Path:              unescapePath(urlReq.RawPath),
+			Query:             Query(urlReq.QueryStringParameters),
+			Headers:           headers,
+			MultiValueQuery:   toMultiValues(urlReq.QueryStringParameters),
+			MultiValueHeaders: toMultiValues(headers),
+			rawCookies:        urlReq.Cookies,
+			rawBody:           urlReq.Body,
+			isBase64Encoded:   urlReq.IsBase64Encoded,
+		},
+		Response: NewResponse[Resp](headers.Get("Accept"), c.encoders),
+		Locals:   make(map[string]any),
+		opts:     c,
+	}
+}
+
+func toFunctionURLResponse(response HttpResponse, err error) (events.LambdaFunctionURLResponse, error) {
+	if err != nil {
+		return events.LambdaFunctionURLResponse{}, err
+	}
+	return events.LambdaFunctionURLResponse{
+		StatusCode: response.StatusCode,
+		Headers:    response.Headers,
+		Body:       string(response.Body),
+	}, nil
+}
+
+func toFunctionURLStreamingResponse(response HttpResponse, err error) (*events.LambdaFunctionURLStreamingResponse, error) {
+	if err != nil {
+		return nil, err
+	}
+	return &events.LambdaFunctionURLStreamingResponse{
+		StatusCode: response.StatusCode,
+		Headers:    response.Headers,
+		Body:       bytes.NewReader(response.Body),
+	}, nil
+}
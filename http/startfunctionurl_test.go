@@ -0,0 +1,192 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jamillosantos/lambda/internal/resources"
+)
+
+func newFunctionURLRequest(method, body string) events.LambdaFunctionURLRequest {
+	return events.LambdaFunctionURLRequest{
+		RawPath:        "/export",
+		Headers:        map[string]string{"content-type": "application/json"},
+		Body:           body,
+		RequestContext: events.LambdaFunctionURLRequestContext{HTTP: events.LambdaFunctionURLRequestContextHTTPDescription{Method: method}},
+	}
+}
+
+// readStreamingResponse reads the prelude and the body sent by a streaming response.
+func readStreamingResponse(t *testing.T, resp *events.LambdaFunctionURLStreamingResponse) (map[string]any, string, error) {
+	t.Helper()
+	data, err := io.ReadAll(resp)
+	prelude, body, found := bytes.Cut(data, make([]byte, 8))
+	require.True(t, found)
+	var p map[string]any
+	require.NoError(t, json.Unmarshal(prelude, &p))
+	return p, string(body), err
+}
+
+func TestStartFunctionURL(t *testing.T) {
+	t.Run("should buffer the response when not streaming", func(t *testing.T) {
+		c := defaultOpts()
+		h := functionURLHandler(func(ctx *Context[albUser, None]) error {
+			ctx.Response.SetCookie(Cookie{Name: "a", Value: "1"})
+			_ = ctx.Response.Flush()
+			return ctx.Response.SendString("hello " + ctx.Request.Body.Name)
+		}, &c, resources.NewStartedManager(nil))
+
+		resp, err := h(context.Background(), newFunctionURLRequest(http.MethodPost, `{"name":"John"}`))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, []string{"a=1"}, resp.Cookies)
+		assert.Equal(t, "hello John", resp.Body)
+	})
+
+	t.Run("should render errors with the error handler", func(t *testing.T) {
+		c := defaultOpts()
+		h := functionURLHandler(func(ctx *Context[albUser, None]) error {
+			return ctx.Response
+		}, &c, resources.NewStartedManager(nil))
+
+		resp, err := h(context.Background(), newFunctionURLRequest(http.MethodPost, `{"name":`))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("should decode the escaped path segments before routing", func(t *testing.T) {
+		r := NewRouter[None, None]()
+		r.GET("/users/{id}", func(ctx *Context[None, None]) error {
+			return ctx.Response.SendString(ctx.Request.PathParams["id"])
+		})
+		c := defaultOpts()
+		h := functionURLHandler(r.Serve, &c, resources.NewStartedManager(nil))
+
+		req := newFunctionURLRequest(http.MethodGet, "")
+		req.RawPath = "/users/a%20b"
+		resp, err := h(context.Background(), req)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "a b", resp.Body)
+	})
+}
+
+func TestStartFunctionURL_Streaming(t *testing.T) {
+	c := defaultOpts()
+	WithResponseStreaming()(&c)
+
+	t.Run("should send the status and the headers on the first flush", func(t *testing.T) {
+		h := functionURLStreamingHandler(func(ctx *Context[None, None]) error {
+			ctx.Response.Status(http.StatusCreated).Header("Content-Type", "text/event-stream")
+			for i := 1; i <= 3; i++ {
+				if _, err := fmt.Fprintf(ctx.Response, "data: %d\n\n", i); err != nil {
+					return err
+				}
+				if err := ctx.Response.Flush(); err != nil {
+					return err
+				}
+			}
+			// Changes after the first flush are not sent.
+			ctx.Response.Status(http.StatusAccepted)
+			_, err := ctx.Response.Write([]byte("data: end\n\n"))
+			return err
+		}, &c, resources.NewStartedManager(nil))
+
+		resp, err := h(context.Background(), newFunctionURLRequest(http.MethodGet, ""))
+		require.NoError(t, err)
+		prelude, body, err := readStreamingResponse(t, resp)
+		require.NoError(t, err)
+		assert.Equal(t, float64(http.StatusCreated), prelude["statusCode"])
+		assert.Equal(t, map[string]any{"Content-Type": "text/event-stream"}, prelude["headers"])
+		assert.Equal(t, "data: 1\n\ndata: 2\n\ndata: 3\n\ndata: end\n\n", body)
+	})
+
+	t.Run("should not compress a flushed response", func(t *testing.T) {
+		h := functionURLStreamingHandler(Use(func(ctx *Context[None, None]) error {
+			ctx.Response.Header("Content-Type", "text/plain")
+			_, _ = ctx.Response.Write([]byte(strings.Repeat("a", 2048)))
+			if err := ctx.Response.Flush(); err != nil {
+				return err
+			}
+			_, err := ctx.Response.Write([]byte(strings.Repeat("b", 2048)))
+			return err
+		}, Compress[None, None]()), &c, resources.NewStartedManager(nil))
+
+		req := newFunctionURLRequest(http.MethodGet, "")
+		req.Headers["accept-encoding"] = "gzip"
+		resp, err := h(context.Background(), req)
+		require.NoError(t, err)
+		prelude, body, err := readStreamingResponse(t, resp)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"Content-Type": "text/plain"}, prelude["headers"])
+		assert.Equal(t, strings.Repeat("a", 2048)+strings.Repeat("b", 2048), body)
+	})
+
+	t.Run("should send the whole response when the handler does not flush", func(t *testing.T) {
+		h := functionURLStreamingHandler(func(ctx *Context[None, None]) error {
+			return ctx.Response.SendString("done")
+		}, &c, resources.NewStartedManager(nil))
+
+		resp, err := h(context.Background(), newFunctionURLRequest(http.MethodGet, ""))
+		require.NoError(t, err)
+		prelude, body, err := readStreamingResponse(t, resp)
+		require.NoError(t, err)
+		assert.Equal(t, float64(http.StatusOK), prelude["statusCode"])
+		assert.Equal(t, "done", body)
+	})
+
+	t.Run("should render errors returned before the first flush", func(t *testing.T) {
+		h := functionURLStreamingHandler(func(ctx *Context[None, None]) error {
+			ctx.Response.Err = &Error{StatusCode: http.StatusConflict, Message: "conflict"}
+			return ctx.Response
+		}, &c, resources.NewStartedManager(nil))
+
+		resp, err := h(context.Background(), newFunctionURLRequest(http.MethodGet, ""))
+		require.NoError(t, err)
+		prelude, body, err := readStreamingResponse(t, resp)
+		require.NoError(t, err)
+		assert.Equal(t, float64(http.StatusConflict), prelude["statusCode"])
+		assert.JSONEq(t, `{"message":"conflict"}`, body)
+	})
+
+	t.Run("should interrupt the stream on errors after the first flush", func(t *testing.T) {
+		failure := errors.New("database failure")
+		h := functionURLStreamingHandler(func(ctx *Context[None, None]) error {
+			_, _ = ctx.Response.Write([]byte("partial"))
+			if err := ctx.Response.Flush(); err != nil {
+				return err
+			}
+			ctx.Response.Err = failure
+			return ctx.Response
+		}, &c, resources.NewStartedManager(nil))
+
+		resp, err := h(context.Background(), newFunctionURLRequest(http.MethodGet, ""))
+		require.NoError(t, err)
+		_, body, err := readStreamingResponse(t, resp)
+		require.ErrorIs(t, err, failure)
+		assert.Equal(t, "partial", body)
+	})
+
+	t.Run("should recover from panics", func(t *testing.T) {
+		h := functionURLStreamingHandler(func(ctx *Context[None, None]) error {
+			panic("boom")
+		}, &c, resources.NewStartedManager(nil))
+
+		resp, err := h(context.Background(), newFunctionURLRequest(http.MethodGet, ""))
+		require.NoError(t, err)
+		prelude, _, err := readStreamingResponse(t, resp)
+		require.NoError(t, err)
+		assert.Equal(t, float64(http.StatusInternalServerError), prelude["statusCode"])
+	})
+}
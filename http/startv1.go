@@ -2,15 +2,11 @@ package http
 
 import (
 	"context"
-	"encoding/base64"
-	"encoding/json"
 	"errors"
-	"fmt"
-	"io"
-	"net/http"
-	"strings"
 
 	"github.com/aws/aws-lambda-go/lambda"
+
+	"github.com/jamillosantos/lambda/internal/resources"
 )
 
 type Handler[Req any, Resp any] func(*Context[Req, Resp]) error
@@ -24,36 +20,59 @@ func StartV1[Req any, Resp any](handler Handler[Req, Resp], opts ...HttpOption)
 		o(&c)
 	}
 
-	startCtx := context.Background()
-	for _, r := range c.resources {
-		if err := r.Start(startCtx); err != nil {
-			panic(fmt.Errorf("failed to start resource %s: %w", r.Name(), err))
+	manager := startManager(&c)
+
+	lambda.StartWithOptions(v1Handler(handler, &c, manager), manager.LambdaOptions()...)
+}
+
+func v1Handler[Req any, Resp any](handler Handler[Req, Resp], c *options, manager *resources.Manager) func(context.Context, APIGatewayProxyRequest) (APIGatewayProxyResponse, error) {
+	return func(ctx context.Context, gatewayReq APIGatewayProxyRequest) (APIGatewayProxyResponse, error) {
+		if err := manager.CheckReady(); err != nil {
+			return toV1Response(c.errorHandler(ctx, serviceUnavailable(err)))
 		}
-	}
 
-	lambda.Start(func(ctx context.Context, gatewayReq APIGatewayProxyRequest) (APIGatewayProxyResponse, error) {
-		req := Request[Req]{
-			HTTPMethod: gatewayReq.HTTPMethod,
-			Path:       gatewayReq.Path,
-			PathParams: gatewayReq.PathParameters,
-			Query:      Query(gatewayReq.QueryStringParameters),
-			Headers:    Headers(gatewayReq.Headers),
-			rawCookies: gatewayReq.MultiValueHeaders["Cookie"],
+		if gatewayReq.Headers == nil {
+			gatewayReq.Headers = lastValues(gatewayReq.MultiValueHeaders)
+		}
+		if gatewayReq.QueryStringParameters == nil {
+			gatewayReq.QueryStringParameters = lastValues(gatewayReq.MultiValueQueryStringParameters)
+		}
+		if gatewayReq.MultiValueHeaders == nil {
+			gatewayReq.MultiValueHeaders = toMultiValues(gatewayReq.Headers)
+		}
+		if gatewayReq.MultiValueQueryStringParameters == nil {
+			gatewayReq.MultiValueQueryStringParameters = toMultiValues(gatewayReq.QueryStringParameters)
 		}
-		resp := Response[Resp]{
-			StatusCode: http.StatusOK,
-			Headers:    make(map[string]string),
+		rawCookies := multiValueHeader(gatewayReq.MultiValueHeaders, "Cookie")
+		if rawCookies == nil {
+			if cookie := headerValue(gatewayReq.Headers, "Cookie"); cookie != "" {
+				rawCookies = []string{cookie}
+			}
 		}
+		req := Request[Req]{
+			HTTPMethod:        gatewayReq.HTTPMethod,
+			Path:              gatewayReq.Path,
+			PathParams:        gatewayReq.PathParameters,
+			Query:             Query(gatewayReq.QueryStringParameters),
+			Headers:           NewHeaders(gatewayReq.Headers),
+			MultiValueQuery:   gatewayReq.MultiValueQueryStringParameters,
+			MultiValueHeaders: canonicalMultiValues(gatewayReq.MultiValueHeaders),
+			rawCookies:        rawCookies,
+			rawBody:           gatewayReq.Body,
+			isBase64Encoded:   gatewayReq.IsBase64Encoded,
+		}
+		resp := NewResponse[Resp](headerValue(gatewayReq.Headers, "Accept"), c.encoders)
 
 		lambdaContext := Context[Req, Resp]{
-			Context:  ctx,
+			Context:  resources.NewContext(ctx, manager),
 			Request:  &req,
-			Response: &resp,
+			Response: resp,
 			Locals:   make(map[string]any),
+			opts:     c,
 		}
 
 		// For the string -> []byte we need to use a more effective way. For now, let's keep the naive approach.
-		err := populateLambdaContextV1(&gatewayReq, &lambdaContext)
+		err := populateBody(gatewayReq.HTTPMethod, gatewayReq.Body, gatewayReq.IsBase64Encoded, req.Headers, &lambdaContext, c)
 		if err != nil {
 			return toV1Response(c.errorHandler(ctx, err))
 		}
@@ -66,35 +85,40 @@ func StartV1[Req any, Resp any](handler Handler[Req, Resp], opts ...HttpOption)
 			return toV1Response(c.errorHandler(ctx, lambdaContext.Response.Err))
 		}
 
-		// lambdaContext.Response.Headers["Set-Cookie"] = strings.Join(lo, "; ")
+		body, isBase64Encoded, err := lambdaContext.Response.GatewayBody()
+		if err != nil {
+			return toV1Response(c.errorHandler(ctx, err))
+		}
 
 		r := APIGatewayProxyResponse{
-			StatusCode: lambdaContext.Response.StatusCode,
-			Headers:    lambdaContext.Response.Headers,
-			Body:       lambdaContext.Response.Body.Bytes(),
+			StatusCode:        lambdaContext.Response.StatusCode,
+			Headers:           lambdaContext.Response.Headers,
+			MultiValueHeaders: toMultiValueHeaders(lambdaContext.Response.Cookies),
+			Body:              body,
+			IsBase64Encoded:   isBase64Encoded,
 		}
 		return r, nil
-	})
+	}
 }
 
 func toV1Response(response HttpResponse, err error) (APIGatewayProxyResponse, error) {
 	if err != nil {
 		return APIGatewayProxyResponse{}, err
 	}
-	return APIGatewayProxyResponse(response), nil
+	return APIGatewayProxyResponse{
+		StatusCode: response.StatusCode,
+		Headers:    response.Headers,
+		Body:       string(response.Body),
+	}, nil
 }
 
-// populateLambdaContextV1 will unmarshal the body from the gateway request into the lambda context.
-func populateLambdaContextV1[Req any, Resp any](gatewayReq *APIGatewayProxyRequest, lambdaContext *Context[Req, Resp]) error {
-	if gatewayReq.HTTPMethod == "GET" {
-		return nil
-	}
-	if len(gatewayReq.Body) == 0 {
+// toMultiValueHeaders returns the multi-value headers of a REST API response, with one Set-Cookie per cookie. REST APIs
+// merge them with the single-value headers.
+func toMultiValueHeaders(cookies []Cookie) map[string][]string {
+	if len(cookies) == 0 {
 		return nil
 	}
-	var reader io.Reader = strings.NewReader(gatewayReq.Body)
-	if gatewayReq.IsBase64Encoded {
-		reader = base64.NewDecoder(base64.StdEncoding, reader)
+	return map[string][]string{
+		"Set-Cookie": toCookieString(cookies),
 	}
-	return json.NewDecoder(reader).Decode(&lambdaContext.Request.Body)
 }
@@ -0,0 +1,141 @@
+package http
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jamillosantos/lambda/internal/resources"
+)
+
+type v1User struct {
+	Name string `json:"name"`
+}
+
+func TestV1Handler(t *testing.T) {
+	c := defaultOpts()
+	manager := resources.NewStartedManager(nil)
+
+	t.Run("should handle single-value requests", func(t *testing.T) {
+		h := v1Handler(func(ctx *Context[v1User, map[string]string]) error {
+			session, _ := ctx.Request.Cookie("session")
+			return ctx.Response.SetCookie(Cookie{Name: "a", Value: "1"}).JSON(map[string]string{
+				"name":    ctx.Request.Body.Name,
+				"q":       ctx.Request.Query.StringDefault("q", ""),
+				"tenant":  ctx.Request.Headers.Get("X-Tenant"),
+				"tags":    ctx.Request.QueryValues("q")[0],
+				"session": session,
+			})
+		}, &c, manager)
+
+		resp, err := h(context.Background(), APIGatewayProxyRequest{
+			HTTPMethod:            http.MethodPost,
+			Path:                  "/users",
+			QueryStringParameters: map[string]string{"q": "hello"},
+			Headers:               map[string]string{"x-tenant": "acme", "cookie": "session=abc", "content-type": "application/json"},
+			Body:                  base64.StdEncoding.EncodeToString([]byte(`{"name":"John"}`)),
+			IsBase64Encoded:       true,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, []string{"a=1"}, resp.MultiValueHeaders["Set-Cookie"])
+		assert.NotContains(t, resp.Headers, "Set-Cookie")
+		assert.False(t, resp.IsBase64Encoded)
+		assert.JSONEq(t, `{"name":"John","q":"hello","tenant":"acme","tags":"hello","session":"abc"}`, resp.Body)
+	})
+
+	t.Run("should handle multi-value requests", func(t *testing.T) {
+		h := v1Handler(func(ctx *Context[None, None]) error {
+			session, _ := ctx.Request.Cookie("session")
+			return ctx.Response.
+				SetCookie(Cookie{Name: "a", Value: "1"}).
+				SetCookie(Cookie{Name: "b", Value: "2"}).
+				SendString(ctx.Request.Query.StringDefault("tag", "") + " " + ctx.Request.Headers.Get("Accept-Language") + " " + session)
+		}, &c, manager)
+
+		resp, err := h(context.Background(), APIGatewayProxyRequest{
+			HTTPMethod:                      http.MethodGet,
+			Path:                            "/",
+			MultiValueQueryStringParameters: map[string][]string{"tag": {"a", "b"}},
+			MultiValueHeaders:               map[string][]string{"accept-language": {"en", "pt"}, "cookie": {"session=abc"}},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a=1", "b=2"}, resp.MultiValueHeaders["Set-Cookie"])
+		assert.Equal(t, "text/plain; charset=utf-8", resp.Headers["Content-Type"])
+		assert.Equal(t, "b pt abc", resp.Body)
+	})
+
+	t.Run("should render errors with the error handler", func(t *testing.T) {
+		h := v1Handler(func(ctx *Context[v1User, None]) error {
+			return ctx.Response
+		}, &c, manager)
+
+		resp, err := h(context.Background(), APIGatewayProxyRequest{
+			HTTPMethod: http.MethodPost,
+			Path:       "/users",
+			Headers:    map[string]string{"Content-Type": "application/json"},
+			Body:       `{"name":`,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Nil(t, resp.MultiValueHeaders)
+	})
+}
+
+func TestToMultiValueHeaders(t *testing.T) {
+	t.Run("should add one Set-Cookie per cookie", func(t *testing.T) {
+		headers := toMultiValueHeaders([]Cookie{
+			{Name: "session", Value: "abc"},
+			{Name: "theme", Value: "dark"},
+		})
+		assert.Equal(t, map[string][]string{
+			"Set-Cookie": {"session=abc", "theme=dark"},
+		}, headers)
+	})
+
+	t.Run("should return nil without cookies", func(t *testing.T) {
+		assert.Nil(t, toMultiValueHeaders(nil))
+	})
+}
+
+func TestAPIGatewayProxyResponse(t *testing.T) {
+	t.Run("should marshal the body as a string", func(t *testing.T) {
+		data, err := json.Marshal(APIGatewayProxyResponse{
+			StatusCode:        200,
+			Headers:           map[string]string{"Content-Type": "application/json"},
+			MultiValueHeaders: map[string][]string{"Set-Cookie": {"a=1", "b=2"}},
+			Body:              `{"ok":true}`,
+		})
+		require.NoError(t, err)
+		assert.JSONEq(t, `{
+			"statusCode": 200,
+			"headers": {"Content-Type": "application/json"},
+			"multiValueHeaders": {"Set-Cookie": ["a=1", "b=2"]},
+			"body": "{\"ok\":true}",
+			"isBase64Encoded": false
+		}`, string(data))
+	})
+}
+
+func TestLastValues(t *testing.T) {
+	t.Run("should keep the last value of each key", func(t *testing.T) {
+		assert.Equal(t, map[string]string{"id": "2", "q": "x"}, lastValues(map[string][]string{
+			"id":    {"1", "2"},
+			"q":     {"x"},
+			"empty": {},
+		}))
+	})
+}
+
+func TestMultiValueHeader(t *testing.T) {
+	t.Run("should match the header name case-insensitively", func(t *testing.T) {
+		headers := map[string][]string{"cookie": {"a=1", "b=2"}}
+		assert.Equal(t, []string{"a=1", "b=2"}, multiValueHeader(headers, "Cookie"))
+		assert.Nil(t, multiValueHeader(headers, "Accept"))
+	})
+}
@@ -2,17 +2,12 @@ package http
 
 import (
 	"context"
-	"encoding/base64"
-	"encoding/json"
 	"errors"
-	"fmt"
-	"io"
-	"net/http"
-	"os"
-	"strings"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
+
+	"github.com/jamillosantos/lambda/internal/resources"
 )
 
 type APIGatewayV2HTTPResponse struct {
@@ -33,37 +28,38 @@ func StartV2[Req any, Resp any](handler Handler[Req, Resp], opts ...HttpOption)
 		o(&c)
 	}
 
-	startCtx := context.Background()
-	for _, r := range c.resources {
-		if err := r.Start(startCtx); err != nil {
-			panic(fmt.Errorf("failed to start resource %s: %w", r.Name(), err))
+	manager := startManager(&c)
+
+	lambda.StartWithOptions(func(ctx context.Context, gatewayReq events.APIGatewayV2HTTPRequest) (APIGatewayV2HTTPResponse, error) {
+		if err := manager.CheckReady(); err != nil {
+			return toV2Response(c.errorHandler(ctx, serviceUnavailable(err)))
 		}
-	}
 
-	lambda.Start(func(ctx context.Context, gatewayReq events.APIGatewayV2HTTPRequest) (APIGatewayV2HTTPResponse, error) {
+		headers := NewHeaders(gatewayReq.Headers)
 		req := Request[Req]{
-			HTTPMethod: gatewayReq.RequestContext.HTTP.Method,
-			Path:       gatewayReq.RawPath,
-			PathParams: gatewayReq.PathParameters,
-			Query:      Query(gatewayReq.QueryStringParameters),
-			Headers:    Headers(gatewayReq.Headers),
-			rawCookies: gatewayReq.Cookies,
-		}
-		resp := Response[Resp]{
-			StatusCode: http.StatusOK,
-			Headers:    make(map[string]string),
-			Cookies:    make([]Cookie, 0),
+			HTTPMethod:        gatewayReq.RequestContext.HTTP.Method,
+			Path:              unescapePath(gatewayReq.RawPath),
+			PathParams:        gatewayReq.PathParameters,
+			Query:             Query(gatewayReq.QueryStringParameters),
+			Headers:           headers,
+			MultiValueQuery:   toMultiValues(gatewayReq.QueryStringParameters),
+			MultiValueHeaders: toMultiValues(headers),
+			rawCookies:        gatewayReq.Cookies,
+			rawBody:           gatewayReq.Body,
+			isBase64Encoded:   gatewayReq.IsBase64Encoded,
 		}
+		resp := NewResponse[Resp](headerValue(gatewayReq.Headers, "Accept"), c.encoders)
 
 		lambdaContext := Context[Req, Resp]{
-			Context:  ctx,
+			Context:  resources.NewContext(ctx, manager),
 			Request:  &req,
-			Response: &resp,
+			Response: resp,
 			Locals:   make(map[string]any),
+			opts:     &c,
 		}
 
 		// For the string -> []byte we need to use a more effective way. For now, let's keep the naive approach.
-		err := populateLambdaContextV2(&gatewayReq, &lambdaContext)
+		err := populateBody(gatewayReq.RequestContext.HTTP.Method, gatewayReq.Body, gatewayReq.IsBase64Encoded, headers, &lambdaContext, &c)
 		if err != nil {
 			return toV2Response(c.errorHandler(ctx, err))
 		}
@@ -76,16 +72,19 @@ func StartV2[Req any, Resp any](handler Handler[Req, Resp], opts ...HttpOption)
 			return toV2Response(c.errorHandler(ctx, lambdaContext.Response.Err))
 		}
 
-		r := APIGatewayV2HTTPResponse{
-			StatusCode: lambdaContext.Response.StatusCode,
-			Headers:    lambdaContext.Response.Headers,
-			Cookies:    toCookieString(lambdaContext.Response.Cookies),
-			Body:       lambdaContext.Response.Body.String(),
+		body, isBase64Encoded, err := lambdaContext.Response.GatewayBody()
+		if err != nil {
+			return toV2Response(c.errorHandler(ctx, err))
 		}
-		_ = json.NewEncoder(os.Stdout).Encode(&r)
-		fmt.Println()
-		return r, nil
-	})
+
+		return APIGatewayV2HTTPResponse{
+			StatusCode:      lambdaContext.Response.StatusCode,
+			Headers:         lambdaContext.Response.Headers,
+			Cookies:         toCookieString(lambdaContext.Response.Cookies),
+			Body:            body,
+			IsBase64Encoded: isBase64Encoded,
+		}, nil
+	}, manager.LambdaOptions()...)
 }
 
 func toCookieString(cookies []Cookie) []string {
@@ -108,18 +107,3 @@ func toV2Response(response HttpResponse, err error) (APIGatewayV2HTTPResponse, e
 		IsBase64Encoded: false,
 	}, nil
 }
-
-// populateLambdaContextV1 will unmarshal the body from the gateway request into the lambda context.
-func populateLambdaContextV2[Req any, Resp any](gatewayReq *events.APIGatewayV2HTTPRequest, lambdaContext *Context[Req, Resp]) error {
-	if gatewayReq.RequestContext.HTTP.Method == "GET" {
-		return nil
-	}
-	if len(gatewayReq.Body) == 0 {
-		return nil
-	}
-	var reader io.Reader = strings.NewReader(gatewayReq.Body)
-	if gatewayReq.IsBase64Encoded {
-		reader = base64.NewDecoder(base64.StdEncoding, reader)
-	}
-	return json.NewDecoder(reader).Decode(&lambdaContext.Request.Body)
-}
@@ -0,0 +1,130 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"sync"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// ErrFlushStream is returned by Response.Flush when the body was set by Response.Stream, which cannot be mixed with
+// incremental writes. The reader is streamed as is when the handler returns.
+var ErrFlushStream = errors.New("cannot flush a response whose body is a stream")
+
+// Write appends data to the body, so the Response can be used as an io.Writer (Eg: by fmt.Fprintf, a csv.Writer or
+// a json.Encoder).
+func (r *Response[T]) Write(p []byte) (int, error) {
+	return r.Body.Write(p)
+}
+
+// Flush sends the status, the headers and the body written so far to the client, when the response is streamed (see
+// WithResponseStreaming). Once flushed, the status and the headers cannot be changed anymore, and the next writes
+// are sent on the next Flush or when the handler returns.
+//
+// When the response is not streamed, Flush does nothing and the body is sent when the handler returns.
+func (r *Response[T]) Flush() error {
+	if r.flush == nil {
+		return nil
+	}
+	return r.flush()
+}
+
+// Streaming reports whether the response is streamed to the client, that is when Flush sends data.
+func (r *Response[T]) Streaming() bool {
+	return r.flush != nil
+}
+
+// responseStreamer streams a Response through a pipe, read by the Lambda runtime.
+type responseStreamer struct {
+	reader  *io.PipeReader
+	writer  *io.PipeWriter
+	started chan struct{}
+	once    sync.Once
+	prelude events.LambdaFunctionURLStreamingResponse
+}
+
+func newResponseStreamer() *responseStreamer {
+	reader, writer := io.Pipe()
+	return &responseStreamer{
+		reader:  reader,
+		writer:  writer,
+		started: make(chan struct{}),
+	}
+}
+
+// isStarted reports whether the response was flushed at least once.
+func (s *responseStreamer) isStarted() bool {
+	select {
+	case <-s.started:
+		return true
+	default:
+		return false
+	}
+}
+
+// attachStreamer makes Response.Flush stream the response.
+func attachStreamer[T any](s *responseStreamer, r *Response[T]) {
+	r.flush = func() error {
+		if r.stream != nil {
+			return ErrFlushStream
+		}
+		s.once.Do(func() {
+			s.prelude = events.LambdaFunctionURLStreamingResponse{
+				StatusCode: r.StatusCode,
+				Headers:    copyHeaders(r.Headers),
+				Cookies:    toCookieString(r.Cookies),
+				Body:       s.reader,
+			}
+			close(s.started)
+		})
+		if r.Body.Len() == 0 {
+			return nil
+		}
+		_, err := s.writer.Write(r.Body.Bytes())
+		r.Body.Reset()
+		return err
+	}
+}
+
+// run calls the handler in a goroutine. It returns the streaming response as soon as the response is flushed for
+// the first time. If the handler returns without flushing, the result of done is returned instead.
+//
+// Failures after the first flush can no longer change the status, they interrupt the stream and are logged.
+func (s *responseStreamer) run(handler func() error, done func(err error) (*events.LambdaFunctionURLStreamingResponse, error)) (*events.LambdaFunctionURLStreamingResponse, error) {
+	result := make(chan error, 1)
+	go func() {
+		var err error
+		defer func() {
+			if p := recover(); p != nil {
+				err = fmt.Errorf("panic: %v", p)
+			}
+			if !s.isStarted() {
+				result <- err
+				return
+			}
+			if err != nil {
+				log.Printf("failed to stream the response: %v", err)
+			}
+			_ = s.writer.CloseWithError(err)
+		}()
+		err = handler()
+	}()
+
+	select {
+	case <-s.started:
+		return &s.prelude, nil
+	case err := <-result:
+		return done(err)
+	}
+}
+
+func copyHeaders(headers map[string]string) map[string]string {
+	r := make(map[string]string, len(headers))
+	for k, v := range headers {
+		r[k] = v
+	}
+	return r
+}
@@ -0,0 +1,132 @@
+package http
+
+import (
+	"net/http"
+	"reflect"
+)
+
+// Typed adapts a handler whose request type describes the whole request, not only its body, into a Handler for any
+// request type. In is a struct whose fields are filled before calling the handler:
+//
+//   - the field with the `body` tag receives the request body, decoded by the Decoder registered for its
+//     Content-Type, as StartV1 and StartV2 decode Req;
+//   - the fields with the `path`, `query`, `header` and `cookie` tags receive the request parameters, see
+//     Request.Bind.
+//
+// For instance:
+//
+//	type UpdateUser struct {
+//		ID     int64    `path:"id"`
+//		DryRun bool     `query:"dry_run"`
+//		Tenant string   `header:"X-Tenant"`
+//		Body   UserBody `body:""`
+//	}
+//
+//	r := http.NewRouter[http.None, User]()
+//	r.PUT("/users/{id}", http.Typed[http.None](updateUser))
+//
+// Failures are reported as StartV1 and StartV2 report them: DecodeError (400), 413 and 415 Error for the body,
+// BindError (400) for the parameters and ValidationError (422) when In, or its body, implements Validator or the
+// validator registered with WithValidator rejects In.
+func Typed[Req any, In any, Resp any](handler Handler[In, Resp]) Handler[Req, Resp] {
+	return func(ctx *Context[Req, Resp]) error {
+		req := ctx.Request
+		typed := &Context[In, Resp]{
+			Context: ctx.Context,
+			Request: &Request[In]{
+				HTTPMethod:        req.HTTPMethod,
+				Path:              req.Path,
+				PathParams:        req.PathParams,
+				Query:             req.Query,
+				Headers:           req.Headers,
+				MultiValueQuery:   req.MultiValueQuery,
+				MultiValueHeaders: req.MultiValueHeaders,
+				rawCookies:        req.rawCookies,
+				rawBody:           req.rawBody,
+				isBase64Encoded:   req.isBase64Encoded,
+			},
+			Response: ctx.Response,
+			Locals:   ctx.Locals,
+			opts:     ctx.opts,
+		}
+		if err := populateTyped(typed); err != nil {
+			ctx.Response.Err = err
+			return ctx.Response
+		}
+		return handler(typed)
+	}
+}
+
+// HandleTyped registers, on the router, a handler whose request type is populated by Typed.
+func HandleTyped[Req any, In any, Resp any](r *Router[Req, Resp], method, pattern string, handler Handler[In, Resp], middlewares ...Middleware[Req, Resp]) *Router[Req, Resp] {
+	return r.Handle(method, pattern, Typed[Req](handler), middlewares...)
+}
+
+// StartTypedV1 is StartV1 for handlers whose request type is populated by Typed.
+func StartTypedV1[In any, Resp any](handler Handler[In, Resp], opts ...HttpOption) {
+	StartV1(Typed[[]byte](handler), opts...)
+}
+
+// StartTypedV2 is StartV2 for handlers whose request type is populated by Typed.
+func StartTypedV2[In any, Resp any](handler Handler[In, Resp], opts ...HttpOption) {
+	StartV2(Typed[[]byte](handler), opts...)
+}
+
+// populateTyped decodes the body into the field with the `body` tag, binds the parameters and validates the request.
+func populateTyped[In any, Resp any](ctx *Context[In, Resp]) error {
+	opts := ctx.opts
+	if opts == nil {
+		o := defaultOpts()
+		opts = &o
+	}
+
+	in := reflect.ValueOf(&ctx.Request.Body).Elem()
+	body, hasBody := typedBodyField(in)
+	if hasBody && ctx.Request.HTTPMethod != http.MethodGet && ctx.Request.rawBody != "" {
+		err := decodeBody(ctx.Request.rawBody, ctx.Request.isBase64Encoded, ctx.Request.Headers.Get("Content-Type"), body.Addr().Interface(), opts)
+		if err != nil {
+			return err
+		}
+	}
+	if err := ctx.Request.Bind(&ctx.Request.Body); err != nil {
+		return err
+	}
+	if hasBody && ctx.Request.HTTPMethod != http.MethodGet {
+		if err := validateValue(body); err != nil {
+			return err
+		}
+	}
+	return validateBody(&ctx.Request.Body, opts.validator)
+}
+
+// typedBodyField returns the field of the struct with the `body` tag.
+func typedBodyField(v reflect.Value) (reflect.Value, bool) {
+	if v.Kind() != reflect.Struct {
+		return reflect.Value{}, false
+	}
+	for i := 0; i < v.NumField(); i++ {
+		if _, ok := v.Type().Field(i).Tag.Lookup("body"); ok && v.Type().Field(i).IsExported() {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// validateValue runs the Validator implemented by the value, if any. Nil values are not validated.
+func validateValue(v reflect.Value) error {
+	if isNil(v) {
+		return nil
+	}
+	var err error
+	if val, ok := v.Interface().(Validator); ok {
+		err = val.Validate()
+	} else if v.CanAddr() {
+		if val, ok := v.Addr().Interface().(Validator); ok {
+			err = val.Validate()
+		}
+	}
+	if err == nil {
+		return nil
+	}
+	return NewValidationError(fieldErrors(err)...)
+}
@@ -0,0 +1,204 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jamillosantos/lambda/internal/resources"
+)
+
+type typedUserBody struct {
+	Name string `json:"name"`
+}
+
+func (b typedUserBody) Validate() error {
+	if b.Name == "" {
+		return FieldError{Field: "name", Message: "is required"}
+	}
+	return nil
+}
+
+type typedUpdateUser struct {
+	ID     int64         `path:"id"`
+	DryRun bool          `query:"dry_run"`
+	Tenant string        `header:"X-Tenant"`
+	Body   typedUserBody `body:""`
+}
+
+func TestTyped(t *testing.T) {
+	var got typedUpdateUser
+	r := NewRouter[None, None]()
+	HandleTyped(r, http.MethodPut, "/users/{id}", func(ctx *Context[typedUpdateUser, None]) error {
+		got = ctx.Request.Body
+		return ctx.Response.Status(http.StatusNoContent)
+	})
+
+	t.Run("should populate the body and the parameters", func(t *testing.T) {
+		got = typedUpdateUser{}
+		ctx := newRouterContext(http.MethodPut, "/users/42")
+		ctx.Request.rawBody = `{"name":"John"}`
+		ctx.Request.Headers = Headers{"X-Tenant": "acme"}
+		ctx.Request.Query = Query{"dry_run": "true"}
+		require.ErrorIs(t, r.Serve(ctx), ctx.Response)
+		require.NoError(t, ctx.Response.Err)
+		assert.Equal(t, typedUpdateUser{ID: 42, DryRun: true, Tenant: "acme", Body: typedUserBody{Name: "John"}}, got)
+		assert.Equal(t, http.StatusNoContent, ctx.Response.StatusCode)
+	})
+
+	t.Run("should decode the body according to its Content-Type", func(t *testing.T) {
+		got = typedUpdateUser{}
+		ctx := newRouterContext(http.MethodPut, "/users/42")
+		ctx.Request.rawBody = "name=Jane"
+		ctx.Request.Headers = Headers{"Content-Type": MIMEApplicationForm}
+		require.ErrorIs(t, r.Serve(ctx), ctx.Response)
+		require.NoError(t, ctx.Response.Err)
+		assert.Equal(t, "Jane", got.Body.Name)
+	})
+
+	t.Run("should report malformed bodies as DecodeError", func(t *testing.T) {
+		ctx := newRouterContext(http.MethodPut, "/users/42")
+		ctx.Request.rawBody = `{"name":`
+		require.ErrorIs(t, r.Serve(ctx), ctx.Response)
+		var decodeErr *DecodeError
+		assert.ErrorAs(t, ctx.Response.Err, &decodeErr)
+	})
+
+	t.Run("should report invalid parameters as BindError", func(t *testing.T) {
+		ctx := newRouterContext(http.MethodPut, "/users/abc")
+		ctx.Request.rawBody = `{"name":"John"}`
+		require.ErrorIs(t, r.Serve(ctx), ctx.Response)
+		var bindErr *BindError
+		require.ErrorAs(t, ctx.Response.Err, &bindErr)
+		assert.Equal(t, "id", bindErr.Params[0].Name)
+	})
+
+	t.Run("should validate the body", func(t *testing.T) {
+		ctx := newRouterContext(http.MethodPut, "/users/42")
+		ctx.Request.rawBody = `{}`
+		require.ErrorIs(t, r.Serve(ctx), ctx.Response)
+		var validationErr *ValidationError
+		require.ErrorAs(t, ctx.Response.Err, &validationErr)
+		assert.Equal(t, []FieldError{{Field: "name", Message: "is required"}}, validationErr.Fields)
+	})
+
+	t.Run("should run the validator registered with WithValidator on the typed request", func(t *testing.T) {
+		opts := defaultOpts()
+		WithValidator(func(v any) error {
+			if _, ok := v.(*typedUpdateUser); !ok {
+				return errors.New("unexpected type")
+			}
+			return FieldError{Field: "tenant", Message: "is not allowed"}
+		})(&opts)
+		ctx := newRouterContext(http.MethodPut, "/users/42")
+		ctx.Request.rawBody = `{"name":"John"}`
+		ctx.opts = &opts
+		require.ErrorIs(t, r.Serve(ctx), ctx.Response)
+		var validationErr *ValidationError
+		require.ErrorAs(t, ctx.Response.Err, &validationErr)
+		assert.Equal(t, []FieldError{{Field: "tenant", Message: "is not allowed"}}, validationErr.Fields)
+	})
+}
+
+func TestTyped_gatewayEvent(t *testing.T) {
+	type tags struct {
+		Tags []string `body:""`
+	}
+	type note struct {
+		Text string `body:""`
+	}
+	var gotUser typedUpdateUser
+	var gotTags tags
+	var gotNote note
+	r := NewRouter[None, None]()
+	HandleTyped(r, http.MethodPut, "/users/{id}", func(ctx *Context[typedUpdateUser, None]) error {
+		gotUser = ctx.Request.Body
+		return ctx.Response.Status(http.StatusNoContent)
+	})
+	HandleTyped(r, http.MethodPost, "/tags", func(ctx *Context[tags, None]) error {
+		gotTags = ctx.Request.Body
+		return ctx.Response.Status(http.StatusNoContent)
+	})
+	HandleTyped(r, http.MethodPost, "/notes", func(ctx *Context[note, None]) error {
+		gotNote = ctx.Request.Body
+		return ctx.Response.Status(http.StatusNoContent)
+	})
+	c := defaultOpts()
+	WithDisallowUnknownFields()(&c)
+	h := v1Handler(r.Serve, &c, resources.NewStartedManager(nil))
+
+	t.Run("should leave the body of None requests to the Typed handlers", func(t *testing.T) {
+		resp, err := h(context.Background(), APIGatewayProxyRequest{
+			HTTPMethod: http.MethodPut,
+			Path:       "/users/42",
+			Headers:    map[string]string{"Content-Type": "application/json", "X-Tenant": "acme"},
+			Body:       `{"name":"John"}`,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+		assert.Equal(t, typedUpdateUser{ID: 42, Tenant: "acme", Body: typedUserBody{Name: "John"}}, gotUser)
+	})
+
+	t.Run("should decode JSON arrays", func(t *testing.T) {
+		resp, err := h(context.Background(), APIGatewayProxyRequest{
+			HTTPMethod: http.MethodPost,
+			Path:       "/tags",
+			Headers:    map[string]string{"Content-Type": "application/json"},
+			Body:       `["a","b"]`,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+		assert.Equal(t, []string{"a", "b"}, gotTags.Tags)
+	})
+
+	t.Run("should decode text bodies", func(t *testing.T) {
+		resp, err := h(context.Background(), APIGatewayProxyRequest{
+			HTTPMethod: http.MethodPost,
+			Path:       "/notes",
+			Headers:    map[string]string{"Content-Type": "text/plain"},
+			Body:       "hello",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+		assert.Equal(t, "hello", gotNote.Text)
+	})
+}
+
+func TestTyped_pointerBody(t *testing.T) {
+	type createUser struct {
+		Body *createUserRequest `body:""`
+	}
+	var got createUser
+	handler := Typed[None](func(ctx *Context[createUser, None]) error {
+		got = ctx.Request.Body
+		return nil
+	})
+
+	t.Run("should not validate a missing body", func(t *testing.T) {
+		got = createUser{}
+		ctx := newRouterContext(http.MethodPost, "/users")
+		require.NoError(t, handler(ctx))
+		require.NoError(t, ctx.Response.Err)
+		assert.Nil(t, got.Body)
+	})
+
+	t.Run("should validate the decoded body", func(t *testing.T) {
+		ctx := newRouterContext(http.MethodPost, "/users")
+		ctx.Request.rawBody = `{"name":"John"}`
+		require.ErrorIs(t, handler(ctx), ctx.Response)
+		var validationErr *ValidationError
+		require.ErrorAs(t, ctx.Response.Err, &validationErr)
+		assert.Equal(t, []FieldError{{Field: "email", Message: "is required"}}, validationErr.Fields)
+	})
+}
+
+func TestValidateValue(t *testing.T) {
+	t.Run("should not panic on values that are not addressable", func(t *testing.T) {
+		assert.NoError(t, validateValue(reflect.ValueOf(createUserRequest{})))
+	})
+}
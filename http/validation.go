@@ -0,0 +1,130 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// Validator is implemented by request bodies that validate themselves. Validate is called after the body is decoded.
+//
+// Returning a FieldError, a ValidationError or the errors.Join of FieldErrors results in a 422 response listing the
+// invalid fields.
+type Validator interface {
+	Validate() error
+}
+
+// FieldError describes why a field of the request is invalid.
+type FieldError struct {
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+func (e FieldError) Error() string {
+	if e.Field == "" {
+		return e.Message
+	}
+	return e.Field + ": " + e.Message
+}
+
+// ValidationError is returned when the request body fails validation. It implements ErrorResponse as a 422
+// Unprocessable Entity listing the invalid fields.
+type ValidationError struct {
+	Fields []FieldError
+}
+
+// NewValidationError creates a ValidationError with the given field errors.
+func NewValidationError(fields ...FieldError) *ValidationError {
+	return &ValidationError{Fields: fields}
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		msgs[i] = f.Error()
+	}
+	return "validation failed: " + strings.Join(msgs, "; ")
+}
+
+func (e *ValidationError) HttpStatusCode() int {
+	return http.StatusUnprocessableEntity
+}
+
+func (e *ValidationError) HttpHeaders() map[string]string {
+	return nil
+}
+
+type validationErrorBody struct {
+	Message string       `json:"message"`
+	Errors  []FieldError `json:"errors"`
+}
+
+func (e *ValidationError) HttpBody() (json.RawMessage, error) {
+	return json.Marshal(validationErrorBody{
+		Message: "Validation Failed",
+		Errors:  e.Fields,
+	})
+}
+
+// validateBody runs the Validator implemented by the body and the validator registered with WithValidator. Failures
+// are returned as a ValidationError. Nil bodies (Eg: a pointer request type and an empty request body) are not
+// validated.
+func validateBody[T any](body *T, validator func(any) error) error {
+	switch any(body).(type) {
+	case *[]byte, *io.Reader:
+		// Raw bodies are not validated.
+		return nil
+	}
+	if isNil(reflect.ValueOf(body).Elem()) {
+		return nil
+	}
+	var err error
+	if v, ok := any(*body).(Validator); ok {
+		err = v.Validate()
+	} else if v, ok := any(body).(Validator); ok {
+		err = v.Validate()
+	}
+	if err == nil && validator != nil {
+		err = validator(body)
+	}
+	if err == nil {
+		return nil
+	}
+	return NewValidationError(fieldErrors(err)...)
+}
+
+// fieldErrors extracts the FieldErrors from err. Errors that are not FieldErrors are reported without field.
+func fieldErrors(err error) []FieldError {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		var fields []FieldError
+		for _, e := range joined.Unwrap() {
+			fields = append(fields, fieldErrors(e)...)
+		}
+		return fields
+	}
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		return validationErr.Fields
+	}
+	var fieldErr FieldError
+	if errors.As(err, &fieldErr) {
+		return []FieldError{fieldErr}
+	}
+	var fieldErrPtr *FieldError
+	if errors.As(err, &fieldErrPtr) {
+		return []FieldError{*fieldErrPtr}
+	}
+	return []FieldError{{Message: err.Error()}}
+}
+
+// isNil reports whether v is a nil pointer or interface, whose methods cannot be called safely.
+func isNil(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		return v.IsNil()
+	}
+	return false
+}
@@ -0,0 +1,114 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type createUserRequest struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+func (r *createUserRequest) Validate() error {
+	var errs []error
+	if r.Name == "" {
+		errs = append(errs, FieldError{Field: "name", Message: "is required"})
+	}
+	if r.Email == "" {
+		errs = append(errs, FieldError{Field: "email", Message: "is required"})
+	}
+	return errors.Join(errs...)
+}
+
+func TestValidateBody(t *testing.T) {
+	t.Run("should call Validate implemented by a pointer receiver", func(t *testing.T) {
+		err := validateBody(&createUserRequest{}, nil)
+		var validationErr *ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, []FieldError{
+			{Field: "name", Message: "is required"},
+			{Field: "email", Message: "is required"},
+		}, validationErr.Fields)
+	})
+
+	t.Run("should succeed when the body is valid", func(t *testing.T) {
+		assert.NoError(t, validateBody(&createUserRequest{Name: "John", Email: "john@example.com"}, nil))
+	})
+
+	t.Run("should call the registered validator", func(t *testing.T) {
+		body := None{}
+		err := validateBody(&body, func(v any) error {
+			assert.Equal(t, &body, v)
+			return errors.New("invalid")
+		})
+		var validationErr *ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, []FieldError{{Message: "invalid"}}, validationErr.Fields)
+	})
+
+	t.Run("should keep the fields of a ValidationError", func(t *testing.T) {
+		wantErr := NewValidationError(FieldError{Field: "age", Message: "must be positive"})
+		err := validateBody(&None{}, func(any) error {
+			return wantErr
+		})
+		assert.Equal(t, wantErr, err)
+	})
+}
+
+func TestValidationError(t *testing.T) {
+	err := NewValidationError(FieldError{Field: "name", Message: "is required"}, FieldError{Message: "invalid"})
+	assert.EqualError(t, err, "validation failed: name: is required; invalid")
+
+	resp, herr := DefaultErrorHandler(context.Background(), err)
+	require.NoError(t, herr)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+	assert.JSONEq(t, `{"message":"Validation Failed","errors":[{"field":"name","message":"is required"},{"message":"invalid"}]}`, string(resp.Body))
+}
+
+func TestPopulateBody_validation(t *testing.T) {
+	newCtx := func() *Context[createUserRequest, None] {
+		return &Context[createUserRequest, None]{Request: &Request[createUserRequest]{}}
+	}
+
+	t.Run("should validate the decoded body", func(t *testing.T) {
+		ctx := newCtx()
+		err := populateBody(http.MethodPost, `{"name":"John"}`, false, nil, ctx, &options{})
+		var validationErr *ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, []FieldError{{Field: "email", Message: "is required"}}, validationErr.Fields)
+	})
+
+	t.Run("should not validate GET requests", func(t *testing.T) {
+		ctx := newCtx()
+		assert.NoError(t, populateBody(http.MethodGet, "", false, nil, ctx, &options{}))
+	})
+
+	t.Run("should not validate an empty body decoded into a pointer", func(t *testing.T) {
+		ctx := &Context[*createUserRequest, None]{Request: &Request[*createUserRequest]{}}
+		assert.NoError(t, populateBody(http.MethodPost, "", false, nil, ctx, &options{}))
+		assert.Nil(t, ctx.Request.Body)
+	})
+
+	t.Run("should validate a body decoded into a pointer", func(t *testing.T) {
+		ctx := &Context[*createUserRequest, None]{Request: &Request[*createUserRequest]{}}
+		err := populateBody(http.MethodPost, `{"name":"John"}`, false, nil, ctx, &options{})
+		var validationErr *ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, []FieldError{{Field: "email", Message: "is required"}}, validationErr.Fields)
+	})
+}
+
+func TestPopulateBody_decoding(t *testing.T) {
+	ctx := &Context[createUserRequest, None]{Request: &Request[createUserRequest]{}}
+	err := populateBody(http.MethodPost, `{"name":`, false, nil, ctx, &options{})
+
+	resp, herr := DefaultErrorHandler(context.Background(), err)
+	require.NoError(t, herr)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
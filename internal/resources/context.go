@@ -0,0 +1,69 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+var (
+	ErrNoResources  = errors.New("no resources available in the context")
+	ErrResourceType = errors.New("unexpected resource type")
+)
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying the Manager.
+func NewContext(ctx context.Context, m *Manager) context.Context {
+	return context.WithValue(ctx, contextKey{}, m)
+}
+
+// FromContext returns the Manager carried by ctx.
+func FromContext(ctx context.Context) (*Manager, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	m, ok := ctx.Value(contextKey{}).(*Manager)
+	return m, ok
+}
+
+// Ready reports whether the named resource of the Manager carried by ctx is ready.
+func Ready(ctx context.Context, name string) bool {
+	m, ok := FromContext(ctx)
+	if !ok {
+		return false
+	}
+	return m.Ready(name)
+}
+
+// Require starts the named resource of the Manager carried by ctx. See Manager.Require.
+func Require(ctx context.Context, name string) error {
+	m, ok := FromContext(ctx)
+	if !ok {
+		return ErrNoResources
+	}
+	return m.Require(ctx, name)
+}
+
+// Get returns the named resource of the Manager carried by ctx. See Manager.Get.
+func Get(ctx context.Context, name string) (Resource, error) {
+	m, ok := FromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNoResources, name)
+	}
+	return m.Get(ctx, name)
+}
+
+// As converts the resource returned by a Get-like function into T.
+func As[T any](r any, err error, name string) (T, error) {
+	var zero T
+	if err != nil {
+		return zero, err
+	}
+	v, ok := r.(T)
+	if !ok {
+		return zero, fmt.Errorf("%w: resource %s is %T, not %s", ErrResourceType, name, r, reflect.TypeFor[T]())
+	}
+	return v, nil
+}
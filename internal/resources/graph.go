@@ -0,0 +1,75 @@
+package resources
+
+import (
+	"fmt"
+	"strings"
+)
+
+type graph struct {
+	dependencies map[string][]string
+}
+
+// newGraph builds the dependency graph of the resources. It fails when two resources share the same name, when a
+// resource depends on a resource that was not registered or when the dependencies form a cycle.
+func newGraph(resources []Resource) (*graph, error) {
+	g := &graph{
+		dependencies: make(map[string][]string, len(resources)),
+	}
+	for _, r := range resources {
+		if _, ok := g.dependencies[r.Name()]; ok {
+			return nil, fmt.Errorf("duplicated resource %s", r.Name())
+		}
+		var deps []string
+		if d, ok := r.(Dependent); ok {
+			deps = d.DependsOn()
+		}
+		if deps == nil {
+			deps = []string{}
+		}
+		g.dependencies[r.Name()] = deps
+	}
+	for _, r := range resources {
+		for _, dep := range g.dependencies[r.Name()] {
+			if _, ok := g.dependencies[dep]; !ok {
+				return nil, fmt.Errorf("resource %s depends on %s, which is not registered", r.Name(), dep)
+			}
+		}
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int, len(resources))
+	var path []string
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case visited:
+			return nil
+		case visiting:
+			for i, n := range path {
+				if n == name {
+					return fmt.Errorf("resource dependency cycle detected: %s", strings.Join(append(path[i:], name), " -> "))
+				}
+			}
+		}
+		state[name] = visiting
+		path = append(path, name)
+		for _, dep := range g.dependencies[name] {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[name] = visited
+		return nil
+	}
+	for _, r := range resources {
+		if err := visit(r.Name()); err != nil {
+			return nil, err
+		}
+	}
+	return g, nil
+}
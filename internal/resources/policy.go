@@ -0,0 +1,73 @@
+package resources
+
+import "time"
+
+// Mode defines when a resource is started.
+type Mode int
+
+const (
+	// ModeEager starts the resource at cold start. A failure aborts the cold start.
+	ModeEager Mode = iota
+	// ModeLazy starts the resource on the first invocation that requires it. A failure is returned to the invocation
+	// and the start is attempted again on the next one.
+	ModeLazy
+	// ModeRetry starts the resource at cold start, retrying with exponential backoff.
+	ModeRetry
+)
+
+const (
+	DefaultMaxAttempts    = 3
+	DefaultInitialBackoff = 100 * time.Millisecond
+	DefaultMaxBackoff     = 5 * time.Second
+)
+
+// Policy defines how a resource is started.
+type Policy struct {
+	Mode  Mode
+	Retry RetryOptions
+}
+
+// RetryOptions configures the ModeRetry policy.
+type RetryOptions struct {
+	// MaxAttempts is the maximum number of start attempts, including the first one. Defaults to 3.
+	MaxAttempts int
+	// InitialBackoff is the time waited after the first failure. It doubles after each failure. Defaults to 100ms.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the time waited between attempts. Defaults to 5s.
+	MaxBackoff time.Duration
+	// Background makes the cold start not wait for the resource. Every invocation fails until the resource is ready.
+	Background bool
+}
+
+func (p Policy) attempts() int {
+	if p.Mode != ModeRetry {
+		return 1
+	}
+	if p.Retry.MaxAttempts <= 0 {
+		return DefaultMaxAttempts
+	}
+	return p.Retry.MaxAttempts
+}
+
+// backoff returns the time to wait after the given failed attempt (starting at 1).
+func (p Policy) backoff(attempt int) time.Duration {
+	initial, max := p.Retry.InitialBackoff, p.Retry.MaxBackoff
+	if initial <= 0 {
+		initial = DefaultInitialBackoff
+	}
+	if max <= 0 {
+		max = DefaultMaxBackoff
+	}
+	d := initial
+	for i := 1; i < attempt && d < max; i++ {
+		d *= 2
+	}
+	if d > max {
+		return max
+	}
+	return d
+}
+
+func (p Policy) background() bool {
+	return p.Mode == ModeRetry && p.Retry.Background
+}
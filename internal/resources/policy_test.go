@@ -0,0 +1,121 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flakyResource fails the first `failures` start attempts.
+type flakyResource struct {
+	name     string
+	failures atomic.Int32
+	attempts atomic.Int32
+}
+
+func newFlakyResource(name string, failures int32) *flakyResource {
+	f := &flakyResource{name: name}
+	f.failures.Store(failures)
+	return f
+}
+
+func (f *flakyResource) Name() string {
+	return f.name
+}
+
+func (f *flakyResource) Start(context.Context) error {
+	if f.attempts.Add(1) <= f.failures.Load() {
+		return errors.New("dial tcp: lookup rds: no such host")
+	}
+	return nil
+}
+
+var fastRetry = RetryOptions{InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+
+func TestPolicy_backoff(t *testing.T) {
+	p := Policy{Mode: ModeRetry, Retry: RetryOptions{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}}
+	assert.Equal(t, 100*time.Millisecond, p.backoff(1))
+	assert.Equal(t, 200*time.Millisecond, p.backoff(2))
+	assert.Equal(t, 400*time.Millisecond, p.backoff(3))
+	assert.Equal(t, time.Second, p.backoff(5))
+}
+
+func TestManager_LazyPolicy(t *testing.T) {
+	t.Run("should not start the resource at cold start", func(t *testing.T) {
+		r := newFlakyResource("db", 0)
+		m := NewManager([]Resource{r}, Options{Policies: map[string]Policy{"db": {Mode: ModeLazy}}})
+		require.NoError(t, m.Start(context.Background()))
+		assert.Equal(t, int32(0), r.attempts.Load())
+		assert.False(t, m.Ready("db"))
+	})
+
+	t.Run("should start the resource when required", func(t *testing.T) {
+		r := newFlakyResource("db", 0)
+		m := NewManager([]Resource{r}, Options{Policies: map[string]Policy{"db": {Mode: ModeLazy}}})
+		require.NoError(t, m.Start(context.Background()))
+		require.NoError(t, m.Require(context.Background(), "db"))
+		require.NoError(t, m.Require(context.Background(), "db"))
+		assert.Equal(t, int32(1), r.attempts.Load())
+		assert.True(t, m.Ready("db"))
+	})
+
+	t.Run("should start the resource again after a failure", func(t *testing.T) {
+		r := newFlakyResource("db", 1)
+		m := NewManager([]Resource{r}, Options{Policies: map[string]Policy{"db": {Mode: ModeLazy}}})
+		require.NoError(t, m.Start(context.Background()))
+		require.Error(t, m.Require(context.Background(), "db"))
+		require.NoError(t, m.Require(context.Background(), "db"))
+		assert.Equal(t, int32(2), r.attempts.Load())
+	})
+
+	t.Run("should fail when the resource is unknown", func(t *testing.T) {
+		m := NewManager(nil, Options{})
+		require.NoError(t, m.Start(context.Background()))
+		require.ErrorIs(t, m.Require(context.Background(), "db"), ErrUnknownResource)
+	})
+}
+
+func TestManager_RetryPolicy(t *testing.T) {
+	t.Run("should retry until the resource starts", func(t *testing.T) {
+		r := newFlakyResource("db", 2)
+		m := NewManager([]Resource{r}, Options{Policies: map[string]Policy{"db": {Mode: ModeRetry, Retry: fastRetry}}})
+		require.NoError(t, m.Start(context.Background()))
+		assert.Equal(t, int32(3), r.attempts.Load())
+		assert.True(t, m.Ready("db"))
+	})
+
+	t.Run("should fail after the max attempts", func(t *testing.T) {
+		r := newFlakyResource("db", 5)
+		retry := fastRetry
+		retry.MaxAttempts = 2
+		m := NewManager([]Resource{r}, Options{Policies: map[string]Policy{"db": {Mode: ModeRetry, Retry: retry}}})
+		require.Error(t, m.Start(context.Background()))
+		assert.Equal(t, int32(2), r.attempts.Load())
+	})
+
+	t.Run("should fail invocations until a background resource is ready", func(t *testing.T) {
+		r := newFlakyResource("db", 1000)
+		retry := fastRetry
+		retry.Background = true
+		retry.MaxAttempts = 1
+		m := NewManager([]Resource{r}, Options{Policies: map[string]Policy{"db": {Mode: ModeRetry, Retry: retry}}})
+		require.NoError(t, m.Start(context.Background()))
+		require.ErrorIs(t, m.CheckReady(), ErrNotReady)
+		require.ErrorIs(t, m.Require(context.Background(), "db"), ErrNotReady)
+
+		r.failures.Store(0)
+		assert.Eventually(t, func() bool {
+			return m.CheckReady() == nil
+		}, time.Second, time.Millisecond)
+	})
+
+	t.Run("should fail when the policy references an unknown resource", func(t *testing.T) {
+		m := NewManager(nil, Options{Policies: map[string]Policy{"db": {Mode: ModeLazy}}})
+		require.ErrorIs(t, m.Start(context.Background()), ErrUnknownResource)
+	})
+}
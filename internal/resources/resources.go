@@ -0,0 +1,363 @@
+// Package resources implements the lifecycle of the resources shared by the lambda and http packages.
+package resources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-lambda-go/lambda"
+)
+
+// DefaultStopTimeout is the time given to the resources to stop when the execution environment is shutting down.
+//
+// Lambda gives the function 500ms between SIGTERM and SIGKILL when only internal extensions are registered.
+const DefaultStopTimeout = 500 * time.Millisecond
+
+var (
+	ErrNotReady        = errors.New("resource not ready")
+	ErrUnknownResource = errors.New("unknown resource")
+)
+
+type Resource interface {
+	Name() string
+	Start(context.Context) error
+}
+
+type Stopper interface {
+	Stop(context.Context) error
+}
+
+type Dependent interface {
+	DependsOn() []string
+}
+
+// StartObserver is called after each resource start attempt with the time it took.
+type StartObserver func(name string, duration time.Duration, err error)
+
+type Options struct {
+	StopTimeout time.Duration
+	OnStart     StartObserver
+	Policies    map[string]Policy
+}
+
+type status int
+
+const (
+	statusPending status = iota
+	statusStarting
+	statusReady
+	statusFailed
+)
+
+type entry struct {
+	resource Resource
+	policy   Policy
+
+	mu     sync.Mutex
+	status status
+	err    error
+	done   chan struct{}
+}
+
+// Manager starts and stops a list of resources.
+type Manager struct {
+	resources []Resource
+	opts      Options
+	graph     *graph
+	entries   map[string]*entry
+
+	mu      sync.Mutex
+	started []Resource
+}
+
+// NewManager creates a Manager for the given resources.
+func NewManager(resources []Resource, opts Options) *Manager {
+	if opts.StopTimeout <= 0 {
+		opts.StopTimeout = DefaultStopTimeout
+	}
+	entries := make(map[string]*entry, len(resources))
+	for _, r := range resources {
+		entries[r.Name()] = &entry{
+			resource: r,
+			policy:   opts.Policies[r.Name()],
+		}
+	}
+	return &Manager{
+		resources: resources,
+		opts:      opts,
+		entries:   entries,
+	}
+}
+
+// NewStartedManager creates a Manager whose resources are considered started without calling their Start method. It is
+// meant for tests injecting fake resources.
+func NewStartedManager(resources []Resource) *Manager {
+	m := NewManager(resources, Options{})
+	for _, e := range m.entries {
+		e.status = statusReady
+	}
+	return m
+}
+
+// Start starts the resources that are not lazy, respecting their dependencies. Resources whose dependencies are
+// started are started concurrently. The dependency graph is validated before anything is started.
+//
+// Resources configured to start in background are not waited for. When a resource fails, the context given to the
+// resources that are still starting is cancelled and the resources that depend on it are not started. All failures
+// are returned joined, after the resources that were already started are stopped in the reverse order, so a failed
+// cold start does not leak their connections and goroutines.
+func (m *Manager) Start(ctx context.Context) error {
+	g, err := newGraph(m.resources)
+	if err != nil {
+		return err
+	}
+	m.graph = g
+	for name := range m.opts.Policies {
+		if _, ok := m.entries[name]; !ok {
+			return fmt.Errorf("%w: policy defined for %s", ErrUnknownResource, name)
+		}
+	}
+
+	startCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	errs := make([]error, len(m.resources))
+	var wg sync.WaitGroup
+	for i, r := range m.resources {
+		e := m.entries[r.Name()]
+		switch {
+		case e.policy.Mode == ModeLazy:
+			continue
+		case e.policy.background():
+			go func() {
+				_ = m.ensure(context.Background(), e, false)
+			}()
+			continue
+		}
+		wg.Add(1)
+		go func(i int, e *entry) {
+			defer wg.Done()
+			if err := m.ensure(startCtx, e, false); err != nil {
+				errs[i] = err
+				cancel()
+			}
+		}(i, e)
+	}
+	wg.Wait()
+	if err := errors.Join(errs...); err != nil {
+		_ = m.Stop(context.WithoutCancel(ctx))
+		return err
+	}
+	return nil
+}
+
+// Require starts the resource, and its dependencies, if it was not started yet. It waits for the resource to be
+// started by a concurrent call. Resources started in background are not waited for: ErrNotReady is returned instead.
+func (m *Manager) Require(ctx context.Context, name string) error {
+	e, ok := m.entries[name]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownResource, name)
+	}
+	if e.policy.background() {
+		if m.Ready(name) {
+			return nil
+		}
+		m.startInBackground(e)
+		return fmt.Errorf("%w: %s", ErrNotReady, name)
+	}
+	return m.ensure(ctx, e, true)
+}
+
+// Get returns the resource with the given name, starting it if needed. See Require.
+func (m *Manager) Get(ctx context.Context, name string) (Resource, error) {
+	if err := m.Require(ctx, name); err != nil {
+		return nil, err
+	}
+	return m.entries[name].resource, nil
+}
+
+// Ready reports whether the resource was started successfully.
+func (m *Manager) Ready(name string) bool {
+	e, ok := m.entries[name]
+	if !ok {
+		return false
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.status == statusReady
+}
+
+// CheckReady fails with ErrNotReady if any of the resources started in background is not ready yet. Resources whose
+// background start has given up are started again.
+//
+// It is called before each invocation, so a pending background resource fails every invocation, whether it uses the
+// resource or not.
+func (m *Manager) CheckReady() error {
+	var errs []error
+	for _, r := range m.resources {
+		e := m.entries[r.Name()]
+		if !e.policy.background() || m.Ready(r.Name()) {
+			continue
+		}
+		m.startInBackground(e)
+		errs = append(errs, fmt.Errorf("%w: %s", ErrNotReady, r.Name()))
+	}
+	return errors.Join(errs...)
+}
+
+func (m *Manager) startInBackground(e *entry) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.status == statusFailed || e.status == statusPending {
+		go func() {
+			_ = m.ensure(context.Background(), e, true)
+		}()
+	}
+}
+
+// ensure starts the resource once. Concurrent calls wait for the same start. A failed start is attempted again only
+// when retryFailed is set, so a failure at cold start is not retried by each resource depending on it.
+func (m *Manager) ensure(ctx context.Context, e *entry, retryFailed bool) error {
+	e.mu.Lock()
+	switch e.status {
+	case statusReady:
+		e.mu.Unlock()
+		return nil
+	case statusFailed:
+		if !retryFailed {
+			defer e.mu.Unlock()
+			return e.err
+		}
+	case statusStarting:
+		done := e.done
+		e.mu.Unlock()
+		select {
+		case <-done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		return e.err
+	}
+	e.status = statusStarting
+	e.done = make(chan struct{})
+	e.mu.Unlock()
+
+	err := m.start(ctx, e, retryFailed)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.err = err
+	if err != nil {
+		e.status = statusFailed
+	} else {
+		e.status = statusReady
+		m.mu.Lock()
+		m.started = append(m.started, e.resource)
+		m.mu.Unlock()
+	}
+	close(e.done)
+	return err
+}
+
+func (m *Manager) start(ctx context.Context, e *entry, retryFailed bool) error {
+	name := e.resource.Name()
+	if err := m.startDependencies(ctx, name, retryFailed); err != nil {
+		return err
+	}
+
+	attempts := e.policy.attempts()
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		startedAt := time.Now()
+		err = e.resource.Start(ctx)
+		if m.opts.OnStart != nil {
+			m.opts.OnStart(name, time.Since(startedAt), err)
+		}
+		if err == nil {
+			return nil
+		}
+		if attempt == attempts {
+			break
+		}
+		select {
+		case <-time.After(e.policy.backoff(attempt)):
+		case <-ctx.Done():
+			return fmt.Errorf("failed to start resource %s: %w", name, errors.Join(err, ctx.Err()))
+		}
+	}
+	return fmt.Errorf("failed to start resource %s: %w", name, err)
+}
+
+func (m *Manager) startDependencies(ctx context.Context, name string, retryFailed bool) error {
+	deps := m.graph.dependencies[name]
+	errs := make([]error, len(deps))
+	var wg sync.WaitGroup
+	for i, dep := range deps {
+		wg.Add(1)
+		go func(i int, dep string) {
+			defer wg.Done()
+			if err := m.ensure(ctx, m.entries[dep], retryFailed); err != nil {
+				errs[i] = fmt.Errorf("resource %s not started: dependency %s failed", name, dep)
+			}
+		}(i, dep)
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// Stop stops the started resources that implement Stopper in the reverse order they were started, so a resource is
+// always stopped before its dependencies. All resources share the same deadline. Failures are logged and returned
+// joined.
+func (m *Manager) Stop(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, m.opts.StopTimeout)
+	defer cancel()
+
+	m.mu.Lock()
+	started := m.started
+	m.started = nil
+	m.mu.Unlock()
+
+	var errs []error
+	for i := len(started) - 1; i >= 0; i-- {
+		r := started[i]
+		s, ok := r.(Stopper)
+		if !ok {
+			continue
+		}
+		if err := s.Stop(ctx); err != nil {
+			log.Printf("failed to stop resource %s: %v", r.Name(), err)
+			errs = append(errs, fmt.Errorf("failed to stop resource %s: %w", r.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// LambdaOptions returns the options that must be passed to lambda.StartWithOptions so the resources are stopped when
+// the execution environment receives SIGTERM. SIGTERM is only enabled when at least one resource implements Stopper.
+func (m *Manager) LambdaOptions() []lambda.Option {
+	for _, r := range m.resources {
+		if _, ok := r.(Stopper); ok {
+			return []lambda.Option{
+				lambda.WithEnableSIGTERM(func() {
+					_ = m.Stop(context.Background())
+				}),
+			}
+		}
+	}
+	return nil
+}
+
+// From converts a list of resources declared by the lambda or http packages into a list of Resource.
+func From[R Resource](rs []R) []Resource {
+	result := make([]Resource, len(rs))
+	for i, r := range rs {
+		result[i] = r
+	}
+	return result
+}
@@ -0,0 +1,242 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recorder struct {
+	mu  sync.Mutex
+	seq []string
+}
+
+func (r *recorder) add(step string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.seq = append(r.seq, step)
+}
+
+type mockResource struct {
+	name     string
+	deps     []string
+	startErr error
+	stopErr  error
+	rec      *recorder
+}
+
+func (m *mockResource) Name() string {
+	return m.name
+}
+
+func (m *mockResource) DependsOn() []string {
+	return m.deps
+}
+
+func (m *mockResource) Start(context.Context) error {
+	m.rec.add("start-" + m.name)
+	return m.startErr
+}
+
+type mockStopper struct {
+	mockResource
+}
+
+func (m *mockStopper) Stop(ctx context.Context) error {
+	if _, ok := ctx.Deadline(); !ok {
+		return errors.New("no deadline")
+	}
+	m.rec.add("stop-" + m.name)
+	return m.stopErr
+}
+
+func TestManager_Start(t *testing.T) {
+	t.Run("should start the dependencies first", func(t *testing.T) {
+		rec := &recorder{}
+		m := NewManager([]Resource{
+			&mockResource{name: "r3", deps: []string{"r2"}, rec: rec},
+			&mockResource{name: "r2", deps: []string{"r1"}, rec: rec},
+			&mockResource{name: "r1", rec: rec},
+		}, Options{})
+		require.NoError(t, m.Start(context.Background()))
+		assert.Equal(t, []string{"start-r1", "start-r2", "start-r3"}, rec.seq)
+	})
+
+	t.Run("should start independent resources concurrently", func(t *testing.T) {
+		rec := &recorder{}
+		var ready sync.WaitGroup
+		ready.Add(2)
+		blocking := func(name string) Resource {
+			return &blockingResource{name: name, ready: &ready}
+		}
+		m := NewManager([]Resource{blocking("r1"), blocking("r2"), &mockResource{name: "r3", rec: rec}}, Options{})
+
+		done := make(chan error)
+		go func() {
+			done <- m.Start(context.Background())
+		}()
+		select {
+		case err := <-done:
+			require.NoError(t, err)
+		case <-time.After(time.Second):
+			t.Fatal("resources were not started concurrently")
+		}
+	})
+
+	t.Run("should report the start duration of each resource", func(t *testing.T) {
+		rec := &recorder{}
+		wantErr := errors.New("connection refused")
+		var mu sync.Mutex
+		reported := make(map[string]error)
+		m := NewManager([]Resource{
+			&mockResource{name: "r1", rec: rec},
+			&mockResource{name: "r2", rec: rec, startErr: wantErr},
+		}, Options{
+			OnStart: func(name string, duration time.Duration, err error) {
+				mu.Lock()
+				defer mu.Unlock()
+				assert.GreaterOrEqual(t, duration, time.Duration(0))
+				reported[name] = err
+			},
+		})
+		require.Error(t, m.Start(context.Background()))
+		assert.Equal(t, map[string]error{"r1": nil, "r2": wantErr}, reported)
+	})
+
+	t.Run("should fail with the name of the resource", func(t *testing.T) {
+		rec := &recorder{}
+		wantErr := errors.New("connection refused")
+		m := NewManager([]Resource{
+			&mockResource{name: "db", rec: rec, startErr: wantErr},
+		}, Options{})
+		err := m.Start(context.Background())
+		require.ErrorIs(t, err, wantErr)
+		assert.EqualError(t, err, "failed to start resource db: connection refused")
+	})
+
+	t.Run("should not start the resources that depend on a failed resource", func(t *testing.T) {
+		rec := &recorder{}
+		m := NewManager([]Resource{
+			&mockResource{name: "db", rec: rec, startErr: errors.New("connection refused")},
+			&mockResource{name: "repository", deps: []string{"db"}, rec: rec},
+		}, Options{})
+		err := m.Start(context.Background())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "resource repository not started: dependency db failed")
+		assert.Equal(t, []string{"start-db"}, rec.seq)
+	})
+
+	t.Run("should stop the started resources when a resource fails", func(t *testing.T) {
+		rec := &recorder{}
+		m := NewManager([]Resource{
+			&mockStopper{mockResource{name: "db", rec: rec}},
+			&mockStopper{mockResource{name: "cache", deps: []string{"db"}, rec: rec}},
+			&mockResource{name: "queue", deps: []string{"cache"}, rec: rec, startErr: errors.New("connection refused")},
+		}, Options{StopTimeout: time.Second})
+		require.Error(t, m.Start(context.Background()))
+		assert.Equal(t, []string{"start-db", "start-cache", "start-queue", "stop-cache", "stop-db"}, rec.seq)
+	})
+
+	t.Run("should fail on cycles before starting anything", func(t *testing.T) {
+		rec := &recorder{}
+		m := NewManager([]Resource{
+			&mockResource{name: "r1", deps: []string{"r2"}, rec: rec},
+			&mockResource{name: "r2", deps: []string{"r3"}, rec: rec},
+			&mockResource{name: "r3", deps: []string{"r1"}, rec: rec},
+		}, Options{})
+		err := m.Start(context.Background())
+		assert.EqualError(t, err, "resource dependency cycle detected: r1 -> r2 -> r3 -> r1")
+		assert.Empty(t, rec.seq)
+	})
+
+	t.Run("should fail on unknown dependencies", func(t *testing.T) {
+		rec := &recorder{}
+		m := NewManager([]Resource{
+			&mockResource{name: "r1", deps: []string{"cache"}, rec: rec},
+		}, Options{})
+		err := m.Start(context.Background())
+		assert.EqualError(t, err, "resource r1 depends on cache, which is not registered")
+	})
+
+	t.Run("should fail on duplicated names", func(t *testing.T) {
+		rec := &recorder{}
+		m := NewManager([]Resource{
+			&mockResource{name: "r1", rec: rec},
+			&mockResource{name: "r1", rec: rec},
+		}, Options{})
+		err := m.Start(context.Background())
+		assert.EqualError(t, err, "duplicated resource r1")
+	})
+}
+
+type blockingResource struct {
+	name  string
+	ready *sync.WaitGroup
+}
+
+func (b *blockingResource) Name() string {
+	return b.name
+}
+
+// Start only returns when all blocking resources were started, which only happens if they run concurrently.
+func (b *blockingResource) Start(context.Context) error {
+	b.ready.Done()
+	b.ready.Wait()
+	return nil
+}
+
+func TestManager_Stop(t *testing.T) {
+	t.Run("should stop the started resources in reverse order", func(t *testing.T) {
+		rec := &recorder{}
+		m := NewManager([]Resource{
+			&mockStopper{mockResource{name: "r1", rec: rec}},
+			&mockResource{name: "r2", deps: []string{"r1"}, rec: rec},
+			&mockStopper{mockResource{name: "r3", deps: []string{"r2"}, rec: rec}},
+		}, Options{StopTimeout: time.Second})
+		require.NoError(t, m.Start(context.Background()))
+		require.NoError(t, m.Stop(context.Background()))
+		assert.Equal(t, []string{"start-r1", "start-r2", "start-r3", "stop-r3", "stop-r1"}, rec.seq)
+	})
+
+	t.Run("should stop all resources and return the failures", func(t *testing.T) {
+		rec := &recorder{}
+		wantErr := errors.New("broken pipe")
+		m := NewManager([]Resource{
+			&mockStopper{mockResource{name: "r1", rec: rec}},
+			&mockStopper{mockResource{name: "r2", deps: []string{"r1"}, rec: rec, stopErr: wantErr}},
+		}, Options{StopTimeout: time.Second})
+		require.NoError(t, m.Start(context.Background()))
+		err := m.Stop(context.Background())
+		require.ErrorIs(t, err, wantErr)
+		assert.EqualError(t, err, "failed to stop resource r2: broken pipe")
+		assert.Equal(t, []string{"start-r1", "start-r2", "stop-r2", "stop-r1"}, rec.seq)
+	})
+
+	t.Run("should not stop resources that were not started", func(t *testing.T) {
+		rec := &recorder{}
+		m := NewManager([]Resource{
+			&mockStopper{mockResource{name: "r1", rec: rec}},
+			&mockStopper{mockResource{name: "r2", deps: []string{"r1"}, rec: rec, startErr: errors.New("failed")}},
+		}, Options{StopTimeout: time.Second})
+		require.Error(t, m.Start(context.Background()))
+		require.NoError(t, m.Stop(context.Background()))
+		assert.Equal(t, []string{"start-r1", "start-r2", "stop-r1"}, rec.seq)
+	})
+}
+
+func TestManager_LambdaOptions(t *testing.T) {
+	t.Run("should not enable SIGTERM when no resource implements Stopper", func(t *testing.T) {
+		m := NewManager([]Resource{&mockResource{name: "r1"}}, Options{})
+		assert.Empty(t, m.LambdaOptions())
+	})
+
+	t.Run("should enable SIGTERM when a resource implements Stopper", func(t *testing.T) {
+		m := NewManager([]Resource{&mockStopper{mockResource{name: "r1"}}}, Options{})
+		assert.Len(t, m.LambdaOptions(), 1)
+	})
+}
@@ -0,0 +1,76 @@
+package lambda
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"sync/atomic"
+)
+
+var keySeq atomic.Uint64
+
+// Locals is implemented by Context and http.Context. It is the storage used by Key.
+type Locals interface {
+	GetLocal(key string) (any, bool)
+	PutLocal(key string, value any)
+}
+
+// Key is a typed key to store values in the locals of a Context or http.Context. Each key created by NewKey is unique,
+// so two middlewares using the same name do not override each other values.
+//
+//	var userKey = lambda.NewKey[*User]("user")
+//
+//	userKey.Set(ctx, user)
+//	user, ok := userKey.Get(ctx)
+type Key[T any] struct {
+	name  string
+	local string
+}
+
+// NewKey creates a new unique Key. The name is only used for debugging.
+func NewKey[T any](name string) Key[T] {
+	return Key[T]{
+		name:  name,
+		local: name + "#" + strconv.FormatUint(keySeq.Add(1), 10),
+	}
+}
+
+// Name returns the name given to NewKey.
+func (k Key[T]) Name() string {
+	return k.name
+}
+
+// LocalKey returns the string under which the value is stored in the locals. It allows typed keys to be used by APIs
+// that accept string keys.
+func (k Key[T]) LocalKey() string {
+	return k.local
+}
+
+// Set stores the value in the locals.
+func (k Key[T]) Set(l Locals, value T) {
+	l.PutLocal(k.local, value)
+}
+
+// Get returns the value stored in the locals. It returns false if the value was not set.
+func (k Key[T]) Get(l Locals) (T, bool) {
+	v, ok := l.GetLocal(k.local)
+	if !ok {
+		var zero T
+		return zero, false
+	}
+	value, ok := v.(T)
+	return value, ok
+}
+
+// MustGet returns the value stored in the locals. It panics if the value was not set or if it is not a T.
+func (k Key[T]) MustGet(l Locals) T {
+	v, ok := l.GetLocal(k.local)
+	if !ok {
+		panic(fmt.Sprintf("local %s not set", k.name))
+	}
+	value, ok := v.(T)
+	if !ok {
+		panic(fmt.Sprintf("local %s is a %T, not a %s", k.name, v, reflect.TypeFor[T]()))
+	}
+	return value
+}
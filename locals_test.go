@@ -0,0 +1,70 @@
+package lambda
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	lambdahttp "github.com/jamillosantos/lambda/http"
+)
+
+type user struct {
+	ID string
+}
+
+func TestKey(t *testing.T) {
+	userKey := NewKey[*user]("user")
+
+	t.Run("should set and get the value on a Context", func(t *testing.T) {
+		ctx := &Context[None]{Locals: map[string]any{}}
+		userKey.Set(ctx, &user{ID: "42"})
+		u, ok := userKey.Get(ctx)
+		require.True(t, ok)
+		assert.Equal(t, "42", u.ID)
+	})
+
+	t.Run("should set and get the value on a http.Context", func(t *testing.T) {
+		ctx := &lambdahttp.Context[lambdahttp.None, lambdahttp.None]{}
+		userKey.Set(ctx, &user{ID: "42"})
+		assert.Equal(t, "42", userKey.MustGet(ctx).ID)
+	})
+
+	t.Run("should not collide with keys with the same name", func(t *testing.T) {
+		otherKey := NewKey[string]("user")
+		ctx := &Context[None]{}
+		userKey.Set(ctx, &user{ID: "42"})
+		otherKey.Set(ctx, "other")
+		assert.Equal(t, "42", userKey.MustGet(ctx).ID)
+		assert.Equal(t, "other", otherKey.MustGet(ctx))
+	})
+
+	t.Run("should not return values set with the string API under the key name", func(t *testing.T) {
+		ctx := &Context[None]{Locals: map[string]any{}}
+		ctx.SetLocal("user", "not a user")
+		_, ok := userKey.Get(ctx)
+		assert.False(t, ok)
+	})
+
+	t.Run("should be accessible through the string API", func(t *testing.T) {
+		ctx := &Context[None]{}
+		userKey.Set(ctx, &user{ID: "42"})
+		v, ok := ctx.GetLocal(userKey.LocalKey())
+		require.True(t, ok)
+		assert.Equal(t, &user{ID: "42"}, v)
+	})
+
+	t.Run("should panic when the value is not set", func(t *testing.T) {
+		assert.PanicsWithValue(t, "local user not set", func() {
+			userKey.MustGet(&Context[None]{})
+		})
+	})
+
+	t.Run("should panic when the value is of another type", func(t *testing.T) {
+		ctx := &Context[None]{Locals: map[string]any{}}
+		ctx.SetLocal(userKey.LocalKey(), "not a user")
+		assert.PanicsWithValue(t, "local user is a string, not a *lambda.user", func() {
+			userKey.MustGet(ctx)
+		})
+	})
+}
@@ -1,8 +1,19 @@
 package lambda
 
+import (
+	"time"
+
+	"github.com/jamillosantos/lambda/internal/resources"
+)
+
 type options[Resp any] struct {
-	resources    []Resource
-	errorHandler func(error) (Resp, error)
+	resources       []Resource
+	errorHandler    func(error) (Resp, error)
+	shutdownTimeout time.Duration
+	startObserver   func(name string, duration time.Duration, err error)
+	policies        map[string]resources.Policy
+	poster          ConnectionPoster
+	concurrency     int
 }
 
 func defaultOpts[Resp any]() options[Resp] {
@@ -27,3 +38,37 @@ func WithErrorHandler[Resp any](h func(error) (Resp, error)) Option[Resp] {
 		o.errorHandler = h
 	}
 }
+
+// WithShutdownTimeout is an option that sets how long the resources have to stop when the execution environment shuts
+// down. The default is 500ms, which is the time Lambda waits between SIGTERM and SIGKILL.
+func WithShutdownTimeout[Resp any](d time.Duration) Option[Resp] {
+	return func(o *options[Resp]) {
+		o.shutdownTimeout = d
+	}
+}
+
+// WithResourceStartObserver is an option that registers a function called after each resource start attempt with the
+// time it took. It is useful to find out which resource dominates the cold start.
+func WithResourceStartObserver[Resp any](f func(name string, duration time.Duration, err error)) Option[Resp] {
+	return func(o *options[Resp]) {
+		o.startObserver = f
+	}
+}
+
+// WithResourcePolicy is an option that defines the policy used to start the resource with the given name.
+func WithResourcePolicy[Resp any](name string, policy ResourcePolicy) Option[Resp] {
+	return func(o *options[Resp]) {
+		if o.policies == nil {
+			o.policies = make(map[string]resources.Policy)
+		}
+		o.policies[name] = policy
+	}
+}
+
+// WithConcurrency is an option that sets how many records of a batch are processed at the same time by StartSQS,
+// StartSNS and StartDynamoDBStream. The default is 1: records are processed one at a time, in order.
+func WithConcurrency[Resp any](n int) Option[Resp] {
+	return func(o *options[Resp]) {
+		o.concurrency = n
+	}
+}
@@ -0,0 +1,129 @@
+package lambda
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+)
+
+// ErrGoneConnection is returned by MemoryPoster when posting to a connection that is not open, as the API Gateway
+// Management API responds 410 Gone.
+var ErrGoneConnection = errors.New("connection is gone")
+
+// ErrNoConnectionPoster is returned by PostToConnection when no ConnectionPoster was set with WithConnectionPoster.
+var ErrNoConnectionPoster = errors.New("no connection poster")
+
+// ConnectionPoster posts messages to the clients connected to a WebSocket API. In production it is implemented by
+// wrapping the PostToConnection operation of the API Gateway Management API client, whose endpoint is returned by
+// WebSocketEndpoint. In tests it can be replaced by a MemoryPoster.
+type ConnectionPoster interface {
+	PostToConnection(ctx context.Context, connectionID string, data []byte) error
+}
+
+// ConnectionPosterFunc is a function that implements ConnectionPoster.
+type ConnectionPosterFunc func(ctx context.Context, connectionID string, data []byte) error
+
+func (f ConnectionPosterFunc) PostToConnection(ctx context.Context, connectionID string, data []byte) error {
+	return f(ctx, connectionID, data)
+}
+
+type posterContextKey struct{}
+
+// WithConnectionPoster is an option that sets the ConnectionPoster used by PostToConnection and PostJSON in the
+// handlers of StartWebSocket.
+func WithConnectionPoster(p ConnectionPoster) Option[WebSocketResponse] {
+	return func(o *options[WebSocketResponse]) {
+		o.poster = p
+	}
+}
+
+// ContextWithConnectionPoster returns a copy of ctx carrying the ConnectionPoster. It is meant to call handlers
+// directly in tests.
+func ContextWithConnectionPoster(ctx context.Context, p ConnectionPoster) context.Context {
+	return context.WithValue(ctx, posterContextKey{}, p)
+}
+
+// PostToConnection posts data to the connection with the ConnectionPoster set with WithConnectionPoster.
+func PostToConnection(ctx context.Context, connectionID string, data []byte) error {
+	p, ok := ctx.Value(posterContextKey{}).(ConnectionPoster)
+	if !ok {
+		return ErrNoConnectionPoster
+	}
+	return p.PostToConnection(ctx, connectionID, data)
+}
+
+// PostJSON posts v, encoded as JSON, to the connection. See PostToConnection.
+func PostJSON(ctx context.Context, connectionID string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return PostToConnection(ctx, connectionID, data)
+}
+
+// WebSocketEndpoint returns the endpoint of the API Gateway Management API for the WebSocket API that sent the
+// request, Eg: "https://abc123.execute-api.us-east-1.amazonaws.com/production".
+func WebSocketEndpoint(request WebSocketRequest) string {
+	return "https://" + request.RequestContext.DomainName + "/" + request.RequestContext.Stage
+}
+
+// MemoryPoster is an in-memory ConnectionPoster for tests. Posting to a connection that was not opened with Connect
+// fails with ErrGoneConnection, unless the poster was created with AcceptAll.
+type MemoryPoster struct {
+	mu          sync.Mutex
+	acceptAll   bool
+	connections map[string]bool
+	messages    map[string][][]byte
+}
+
+// NewMemoryPoster creates a MemoryPoster with the given connections open.
+func NewMemoryPoster(connectionIDs ...string) *MemoryPoster {
+	p := &MemoryPoster{
+		connections: make(map[string]bool),
+		messages:    make(map[string][][]byte),
+	}
+	for _, id := range connectionIDs {
+		p.connections[id] = true
+	}
+	return p
+}
+
+// AcceptAll makes the poster accept messages to any connection.
+func (p *MemoryPoster) AcceptAll() *MemoryPoster {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.acceptAll = true
+	return p
+}
+
+// Connect opens the connection.
+func (p *MemoryPoster) Connect(connectionID string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.connections[connectionID] = true
+}
+
+// Disconnect closes the connection. Its messages are kept.
+func (p *MemoryPoster) Disconnect(connectionID string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.connections, connectionID)
+}
+
+func (p *MemoryPoster) PostToConnection(_ context.Context, connectionID string, data []byte) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.acceptAll && !p.connections[connectionID] {
+		return ErrGoneConnection
+	}
+	p.messages[connectionID] = append(p.messages[connectionID], append([]byte(nil), data...))
+	return nil
+}
+
+// Messages returns the messages posted to the connection, in order.
+func (p *MemoryPoster) Messages(connectionID string) [][]byte {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([][]byte(nil), p.messages[connectionID]...)
+}
@@ -1,6 +1,21 @@
 package lambda
 
-import "context"
+import (
+	"context"
+
+	"github.com/jamillosantos/lambda/internal/resources"
+)
+
+var (
+	// ErrResourceNotReady is returned when a resource started in background is not ready yet.
+	ErrResourceNotReady = resources.ErrNotReady
+	// ErrUnknownResource is returned when a resource is referenced by a name that was not registered.
+	ErrUnknownResource = resources.ErrUnknownResource
+	// ErrResourceType is returned by ResourceOf when the resource is not of the requested type.
+	ErrResourceType = resources.ErrResourceType
+	// ErrNoResources is returned by ResourceOf when the context does not carry any resources.
+	ErrNoResources = resources.ErrNoResources
+)
 
 // Resource is an interface that represents a resource that can be started and stopped.
 // Example: A connection to the database or message broker, etc;
@@ -8,3 +23,77 @@ type Resource interface {
 	Name() string
 	Start(context.Context) error
 }
+
+// Stopper is an optional interface implemented by resources that need to be released when the execution environment
+// shuts down. Resources are stopped in the reverse order they were started.
+type Stopper interface {
+	Stop(context.Context) error
+}
+
+// Dependent is an optional interface implemented by resources that can only be started after other resources. The
+// dependencies are referenced by their Name. Resources that do not depend on each other are started concurrently.
+type Dependent interface {
+	DependsOn() []string
+}
+
+// ResourcePolicy defines when a resource is started and what happens when it fails. Use EagerPolicy, LazyPolicy or
+// RetryPolicy to create one and WithResourcePolicy to assign it to a resource.
+type ResourcePolicy = resources.Policy
+
+// RetryOptions configures RetryPolicy.
+type RetryOptions = resources.RetryOptions
+
+// EagerPolicy starts the resource at cold start. A failure aborts the cold start. This is the default policy.
+func EagerPolicy() ResourcePolicy {
+	return ResourcePolicy{Mode: resources.ModeEager}
+}
+
+// LazyPolicy starts the resource on the first invocation that requires it (see Context.RequireResource). A failure is
+// returned to that invocation and the start is attempted again by the next one.
+func LazyPolicy() ResourcePolicy {
+	return ResourcePolicy{Mode: resources.ModeLazy}
+}
+
+// RetryPolicy starts the resource at cold start retrying it with exponential backoff. When RetryOptions.Background is
+// set, the cold start does not wait for the resource and invocations fail with ErrResourceNotReady until it is ready.
+// Every invocation fails meanwhile, including the ones that do not use the resource: prefer LazyPolicy for resources
+// only needed by some of them.
+func RetryPolicy(opts RetryOptions) ResourcePolicy {
+	return ResourcePolicy{Mode: resources.ModeRetry, Retry: opts}
+}
+
+// ResourceProvider is implemented by Context and http.Context. It gives access to the resources registered with
+// WithResources.
+type ResourceProvider interface {
+	Resource(name string) (any, error)
+}
+
+// ResourceOf returns the resource registered with the given name as T. Resources with LazyPolicy are started on the
+// first call. It fails with ErrUnknownResource when no resource was registered with that name and with ErrResourceType
+// when the resource is not a T.
+//
+//	pool, err := lambda.ResourceOf[*pgxpool.Pool](ctx, "db")
+func ResourceOf[T any](ctx ResourceProvider, name string) (T, error) {
+	r, err := ctx.Resource(name)
+	return resources.As[T](r, err, name)
+}
+
+// ContextWithResources returns a copy of ctx carrying the given resources, considered as started. It is meant for
+// tests that inject fake resources in a Context.
+func ContextWithResources(ctx context.Context, rs ...Resource) context.Context {
+	return resources.NewContext(ctx, resources.NewStartedManager(resources.From(rs)))
+}
+
+// startManager starts the resources registered in the options, at cold start. It panics when they fail to start, as
+// the execution environment cannot serve any invocation.
+func startManager[Resp any](c *options[Resp]) *resources.Manager {
+	manager := resources.NewManager(resources.From(c.resources), resources.Options{
+		StopTimeout: c.shutdownTimeout,
+		OnStart:     c.startObserver,
+		Policies:    c.policies,
+	})
+	if err := manager.Start(context.Background()); err != nil {
+		panic(err)
+	}
+	return manager
+}
@@ -0,0 +1,130 @@
+package lambda
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+
+	"github.com/jamillosantos/lambda/internal/resources"
+)
+
+// SNSMessage is the request of the handlers of StartSNS and SNSFromSQS: the SNS notification and its Message decoded
+// into Msg.
+type SNSMessage[Msg any] struct {
+	Notification events.SNSEntity
+	Message      Msg
+}
+
+// ID returns the ID of the SNS message.
+func (m SNSMessage[Msg]) ID() string {
+	return m.Notification.MessageID
+}
+
+// TopicARN returns the ARN of the topic the message was published to. It is empty for messages delivered to SQS with
+// raw message delivery, which do not carry it.
+func (m SNSMessage[Msg]) TopicARN() string {
+	return m.Notification.TopicArn
+}
+
+// Attribute returns the value of the message attribute with the given name.
+func (m SNSMessage[Msg]) Attribute(name string) (string, bool) {
+	attr, ok := m.Notification.MessageAttributes[name].(map[string]any)
+	if !ok {
+		return "", false
+	}
+	value, ok := attr["Value"].(string)
+	return value, ok
+}
+
+// StartSNS will start the lambda function, subscribed to an SNS topic, with the given handler and options.
+//
+// The handler is called once per record, with its Message decoded as JSON into Msg (string and []byte receive the
+// message as is). Middlewares are added with Use. Failures are returned, joined, as the error of the invocation so SNS
+// retries the delivery.
+func StartSNS[Msg any](handler Handler[SNSMessage[Msg], None], opts ...Option[None]) {
+	c := defaultOpts[None]()
+	for _, o := range opts {
+		o(&c)
+	}
+
+	manager := startManager(&c)
+
+	lambda.StartWithOptions(snsHandler(handler, &c, manager), manager.LambdaOptions()...)
+}
+
+func snsHandler[Msg any](handler Handler[SNSMessage[Msg], None], c *options[None], manager *resources.Manager) func(context.Context, events.SNSEvent) error {
+	return func(ctx context.Context, event events.SNSEvent) error {
+		if err := manager.CheckReady(); err != nil {
+			return err
+		}
+		ctx = resources.NewContext(ctx, manager)
+
+		failures := processBatch(c.concurrency, groupBy(len(event.Records), noGroup), func(i int) error {
+			return handleSNS(ctx, handler, event.Records[i].SNS)
+		})
+
+		var errs []error
+		for i, record := range event.Records {
+			if err, ok := failures[i]; ok {
+				errs = append(errs, fmt.Errorf("failed to process SNS message %s: %w", record.SNS.MessageID, err))
+			}
+		}
+		return errors.Join(errs...)
+	}
+}
+
+// SNSFromSQS adapts a handler of SNS messages into a handler for StartSQS, for SQS queues subscribed to SNS topics.
+// Both delivery modes are detected: the SNS envelope is unwrapped when raw message delivery is disabled, otherwise the
+// body is the message itself and the attributes are the SQS message attributes.
+//
+//	lambda.StartSQS(lambda.SNSFromSQS(handler))
+func SNSFromSQS[Msg any](handler Handler[SNSMessage[Msg], None]) Handler[SQSMessage[string], None] {
+	return func(ctx *Context[SQSMessage[string]]) (None, error) {
+		return None{}, handleSNS(ctx.Context, handler, snsEntityFromSQS(ctx.Request.Record))
+	}
+}
+
+func handleSNS[Msg any](ctx context.Context, handler Handler[SNSMessage[Msg], None], notification events.SNSEntity) error {
+	msg, err := decodeMessage[Msg](notification.Message)
+	if err != nil {
+		return err
+	}
+	_, err = handler(&Context[SNSMessage[Msg]]{
+		Context: ctx,
+		Request: SNSMessage[Msg]{Notification: notification, Message: msg},
+		Locals:  make(map[string]any),
+	})
+	return err
+}
+
+// snsEntityFromSQS returns the SNS notification delivered in the SQS record.
+func snsEntityFromSQS(record events.SQSMessage) events.SNSEntity {
+	var envelope events.SNSEntity
+	if err := json.Unmarshal([]byte(record.Body), &envelope); err == nil && envelope.Type == "Notification" && envelope.TopicArn != "" {
+		return envelope
+	}
+
+	// Raw message delivery.
+	attributes := make(map[string]any, len(record.MessageAttributes))
+	for name, attr := range record.MessageAttributes {
+		value := map[string]any{"Type": attr.DataType}
+		if attr.StringValue != nil {
+			value["Value"] = *attr.StringValue
+		}
+		attributes[name] = value
+	}
+	return events.SNSEntity{
+		MessageID:         record.MessageId,
+		Type:              "Notification",
+		MessageAttributes: attributes,
+		Message:           record.Body,
+	}
+}
+
+func noGroup(int) string {
+	return ""
+}
@@ -0,0 +1,101 @@
+package lambda
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jamillosantos/lambda/internal/resources"
+)
+
+func newSNSRecord(id, message string) events.SNSEventRecord {
+	return events.SNSEventRecord{SNS: events.SNSEntity{
+		MessageID: id,
+		Type:      "Notification",
+		TopicArn:  "arn:aws:sns:us-east-1:123456789012:orders",
+		Message:   message,
+		MessageAttributes: map[string]any{
+			"tenant": map[string]any{"Type": "String", "Value": "acme"},
+		},
+	}}
+}
+
+func TestStartSNS(t *testing.T) {
+	t.Run("should decode the message and expose its metadata", func(t *testing.T) {
+		var got []SNSMessage[order]
+		c := defaultOpts[None]()
+		h := snsHandler(func(ctx *Context[SNSMessage[order]]) (None, error) {
+			got = append(got, ctx.Request)
+			return None{}, nil
+		}, &c, resources.NewStartedManager(nil))
+
+		require.NoError(t, h(context.Background(), events.SNSEvent{Records: []events.SNSEventRecord{newSNSRecord("m1", `{"id":"1"}`)}}))
+		require.Len(t, got, 1)
+		assert.Equal(t, order{ID: "1"}, got[0].Message)
+		assert.Equal(t, "m1", got[0].ID())
+		assert.Equal(t, "arn:aws:sns:us-east-1:123456789012:orders", got[0].TopicARN())
+		tenant, ok := got[0].Attribute("tenant")
+		assert.True(t, ok)
+		assert.Equal(t, "acme", tenant)
+	})
+
+	t.Run("should return the failures", func(t *testing.T) {
+		failure := errors.New("failure")
+		c := defaultOpts[None]()
+		h := snsHandler(func(ctx *Context[SNSMessage[order]]) (None, error) {
+			return None{}, failure
+		}, &c, resources.NewStartedManager(nil))
+
+		err := h(context.Background(), events.SNSEvent{Records: []events.SNSEventRecord{newSNSRecord("m1", `{}`), newSNSRecord("m2", `{`)}})
+		require.ErrorIs(t, err, failure)
+		assert.Contains(t, err.Error(), "failed to process SNS message m2")
+	})
+}
+
+func TestSNSFromSQS(t *testing.T) {
+	var got []SNSMessage[order]
+	c := defaultOpts[events.SQSEventResponse]()
+	h := sqsHandler(SNSFromSQS(func(ctx *Context[SNSMessage[order]]) (None, error) {
+		got = append(got, ctx.Request)
+		return None{}, nil
+	}), &c, resources.NewStartedManager(nil))
+
+	t.Run("should unwrap the SNS envelope", func(t *testing.T) {
+		got = nil
+		resp, err := h(context.Background(), events.SQSEvent{Records: []events.SQSMessage{newSQSRecord("sqs-1", `{
+			"Type": "Notification",
+			"MessageId": "sns-1",
+			"TopicArn": "arn:aws:sns:us-east-1:123456789012:orders",
+			"Message": "{\"id\":\"1\"}",
+			"MessageAttributes": {"tenant": {"Type": "String", "Value": "acme"}}
+		}`, nil)}})
+		require.NoError(t, err)
+		assert.Empty(t, resp.BatchItemFailures)
+		require.Len(t, got, 1)
+		assert.Equal(t, order{ID: "1"}, got[0].Message)
+		assert.Equal(t, "sns-1", got[0].ID())
+		assert.Equal(t, "arn:aws:sns:us-east-1:123456789012:orders", got[0].TopicARN())
+		tenant, _ := got[0].Attribute("tenant")
+		assert.Equal(t, "acme", tenant)
+	})
+
+	t.Run("should read raw deliveries as the message itself", func(t *testing.T) {
+		got = nil
+		tenant := "acme"
+		record := newSQSRecord("sqs-1", `{"id":"2"}`, nil)
+		record.MessageAttributes = map[string]events.SQSMessageAttribute{"tenant": {StringValue: &tenant, DataType: "String"}}
+		_, err := h(context.Background(), events.SQSEvent{Records: []events.SQSMessage{record}})
+		require.NoError(t, err)
+		require.Len(t, got, 1)
+		assert.Equal(t, order{ID: "2"}, got[0].Message)
+		assert.Equal(t, "sqs-1", got[0].ID())
+		assert.Empty(t, got[0].TopicARN())
+		v, ok := got[0].Attribute("tenant")
+		assert.True(t, ok)
+		assert.Equal(t, "acme", v)
+	})
+}
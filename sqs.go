@@ -0,0 +1,102 @@
+package lambda
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+
+	"github.com/jamillosantos/lambda/internal/resources"
+)
+
+// SQSMessage is the request of the handlers of StartSQS: the SQS record and its body decoded into Msg.
+type SQSMessage[Msg any] struct {
+	Record  events.SQSMessage
+	Message Msg
+}
+
+// ID returns the ID of the SQS message.
+func (m SQSMessage[Msg]) ID() string {
+	return m.Record.MessageId
+}
+
+// ReceiveCount returns how many times the message was received, including this one. It is 0 when SQS did not send
+// the ApproximateReceiveCount attribute.
+func (m SQSMessage[Msg]) ReceiveCount() int {
+	n, _ := strconv.Atoi(m.Record.Attributes["ApproximateReceiveCount"])
+	return n
+}
+
+// Attribute returns the string value of the message attribute with the given name.
+func (m SQSMessage[Msg]) Attribute(name string) (string, bool) {
+	attr, ok := m.Record.MessageAttributes[name]
+	if !ok || attr.StringValue == nil {
+		return "", false
+	}
+	return *attr.StringValue, true
+}
+
+// StartSQS will start the lambda function, as the consumer of an SQS queue, with the given handler and options.
+//
+// The handler is called once per message, with its body decoded as JSON into Msg (string and []byte receive the body
+// as is). Per-message middlewares are added with Use. The messages that fail to be decoded or processed are reported
+// as batch item failures, so only those are retried: the event source mapping must have ReportBatchItemFailures
+// enabled.
+//
+// Messages are processed one at a time, unless WithConcurrency is set. For FIFO queues, messages of the same group are
+// always processed in order and, once one fails, the next ones of its group are reported as failures without being
+// processed, so the order is kept when they are retried.
+func StartSQS[Msg any](handler Handler[SQSMessage[Msg], None], opts ...Option[events.SQSEventResponse]) {
+	c := defaultOpts[events.SQSEventResponse]()
+	for _, o := range opts {
+		o(&c)
+	}
+
+	manager := startManager(&c)
+
+	lambda.StartWithOptions(sqsHandler(handler, &c, manager), manager.LambdaOptions()...)
+}
+
+func sqsHandler[Msg any](handler Handler[SQSMessage[Msg], None], c *options[events.SQSEventResponse], manager *resources.Manager) func(context.Context, events.SQSEvent) (events.SQSEventResponse, error) {
+	return func(ctx context.Context, event events.SQSEvent) (events.SQSEventResponse, error) {
+		if err := manager.CheckReady(); err != nil {
+			return events.SQSEventResponse{}, err
+		}
+		ctx = resources.NewContext(ctx, manager)
+
+		groups := groupBy(len(event.Records), func(i int) string {
+			record := event.Records[i]
+			if !strings.HasSuffix(record.EventSourceARN, ".fifo") {
+				return ""
+			}
+			return record.Attributes["MessageGroupId"]
+		})
+		failures := processBatch(c.concurrency, groups, func(i int) error {
+			record := event.Records[i]
+			msg, err := decodeMessage[Msg](record.Body)
+			if err != nil {
+				return err
+			}
+			_, err = handler(&Context[SQSMessage[Msg]]{
+				Context: ctx,
+				Request: SQSMessage[Msg]{Record: record, Message: msg},
+				Locals:  make(map[string]any),
+			})
+			return err
+		})
+
+		resp := events.SQSEventResponse{
+			BatchItemFailures: make([]events.SQSBatchItemFailure, 0, len(failures)),
+		}
+		for i, record := range event.Records {
+			if err, ok := failures[i]; ok {
+				log.Printf("failed to process SQS message %s: %v", record.MessageId, err)
+				resp.BatchItemFailures = append(resp.BatchItemFailures, events.SQSBatchItemFailure{ItemIdentifier: record.MessageId})
+			}
+		}
+		return resp, nil
+	}
+}
@@ -0,0 +1,107 @@
+package lambda
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jamillosantos/lambda/internal/resources"
+)
+
+type order struct {
+	ID string `json:"id"`
+}
+
+func newSQSRecord(id, body string, attributes map[string]string) events.SQSMessage {
+	return events.SQSMessage{
+		MessageId:      id,
+		Body:           body,
+		Attributes:     attributes,
+		EventSourceARN: "arn:aws:sqs:us-east-1:123456789012:orders",
+	}
+}
+
+func TestStartSQS(t *testing.T) {
+	t.Run("should report the failed messages", func(t *testing.T) {
+		var (
+			mu        sync.Mutex
+			processed []string
+		)
+		c := defaultOpts[events.SQSEventResponse]()
+		WithConcurrency[events.SQSEventResponse](4)(&c)
+		h := sqsHandler(func(ctx *Context[SQSMessage[order]]) (None, error) {
+			mu.Lock()
+			processed = append(processed, ctx.Request.Message.ID)
+			mu.Unlock()
+			if ctx.Request.Message.ID == "2" {
+				return None{}, errors.New("failure")
+			}
+			return None{}, nil
+		}, &c, resources.NewStartedManager(nil))
+
+		resp, err := h(context.Background(), events.SQSEvent{Records: []events.SQSMessage{
+			newSQSRecord("m1", `{"id":"1"}`, nil),
+			newSQSRecord("m2", `{"id":"2"}`, nil),
+			newSQSRecord("m3", `{"id":`, nil),
+			newSQSRecord("m4", `{"id":"4"}`, nil),
+		}})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"1", "2", "4"}, processed)
+		assert.Equal(t, []events.SQSBatchItemFailure{{ItemIdentifier: "m2"}, {ItemIdentifier: "m3"}}, resp.BatchItemFailures)
+	})
+
+	t.Run("should keep the order of the message groups of FIFO queues", func(t *testing.T) {
+		var processed []string
+		c := defaultOpts[events.SQSEventResponse]()
+		h := sqsHandler(func(ctx *Context[SQSMessage[string]]) (None, error) {
+			processed = append(processed, ctx.Request.ID())
+			if ctx.Request.ID() == "a1" {
+				return None{}, errors.New("failure")
+			}
+			return None{}, nil
+		}, &c, resources.NewStartedManager(nil))
+
+		fifo := func(id, group string) events.SQSMessage {
+			r := newSQSRecord(id, "", map[string]string{"MessageGroupId": group})
+			r.EventSourceARN = "arn:aws:sqs:us-east-1:123456789012:orders.fifo"
+			return r
+		}
+		resp, err := h(context.Background(), events.SQSEvent{Records: []events.SQSMessage{
+			fifo("a1", "a"), fifo("b1", "b"), fifo("a2", "a"), fifo("b2", "b"),
+		}})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a1", "b1", "b2"}, processed)
+		assert.Equal(t, []events.SQSBatchItemFailure{{ItemIdentifier: "a1"}, {ItemIdentifier: "a2"}}, resp.BatchItemFailures)
+	})
+
+	t.Run("should give middlewares access to the attributes and the receive count", func(t *testing.T) {
+		var tenants []string
+		c := defaultOpts[events.SQSEventResponse]()
+		tenant := "acme"
+		h := sqsHandler(Use(func(ctx *Context[SQSMessage[order]]) (None, error) {
+			return None{}, nil
+		}, func(ctx *Context[SQSMessage[order]], next Handler[SQSMessage[order], None]) (None, error) {
+			if ctx.Request.ReceiveCount() > 3 {
+				return None{}, errors.New("too many attempts")
+			}
+			v, _ := ctx.Request.Attribute("tenant")
+			tenants = append(tenants, v)
+			return next(ctx)
+		}), &c, resources.NewStartedManager(nil))
+
+		first := newSQSRecord("m1", `{}`, map[string]string{"ApproximateReceiveCount": "1"})
+		first.MessageAttributes = map[string]events.SQSMessageAttribute{"tenant": {StringValue: &tenant, DataType: "String"}}
+		resp, err := h(context.Background(), events.SQSEvent{Records: []events.SQSMessage{
+			first,
+			newSQSRecord("m2", `{}`, map[string]string{"ApproximateReceiveCount": "4"}),
+		}})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"acme"}, tenants)
+		assert.Equal(t, []events.SQSBatchItemFailure{{ItemIdentifier: "m2"}}, resp.BatchItemFailures)
+	})
+}
@@ -2,8 +2,10 @@ package lambda
 
 import (
 	"context"
-	"fmt"
+
 	"github.com/aws/aws-lambda-go/lambda"
+
+	"github.com/jamillosantos/lambda/internal/resources"
 )
 
 type Handler[Req any, Resp any] func(*Context[Req]) (Resp, error)
@@ -17,16 +19,16 @@ func Start[Req any, Resp any](handler Handler[Req, Resp], opts ...Option[Resp])
 		o(&c)
 	}
 
-	startCtx := context.Background()
-	for _, r := range c.resources {
-		if err := r.Start(startCtx); err != nil {
-			panic(fmt.Errorf("failed to start resource %s: %w", r.Name(), err))
+	manager := startManager(&c)
+
+	lambda.StartWithOptions(func(ctx context.Context, request Req) (Resp, error) {
+		if err := manager.CheckReady(); err != nil {
+			var resp Resp
+			return resp, err
 		}
-	}
 
-	lambda.Start(func(ctx context.Context, request Req) (Resp, error) {
 		lambdaContext := Context[Req]{
-			Context: ctx,
+			Context: resources.NewContext(ctx, manager),
 			Request: request,
 			Locals:  make(map[string]any),
 		}
@@ -37,5 +39,5 @@ func Start[Req any, Resp any](handler Handler[Req, Resp], opts ...Option[Resp])
 		}
 
 		return resp, nil
-	})
+	}, manager.LambdaOptions()...)
 }
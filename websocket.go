@@ -0,0 +1,177 @@
+package lambda
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+
+	"github.com/jamillosantos/lambda/internal/resources"
+)
+
+// Route keys of the predefined routes of a WebSocket API.
+const (
+	RouteConnect    = "$connect"
+	RouteDisconnect = "$disconnect"
+	RouteDefault    = "$default"
+)
+
+// ErrRouteNotFound is returned when no route matches the route key of a WebSocket event and no $default route is
+// registered.
+var ErrRouteNotFound = errors.New("websocket route not found")
+
+type (
+	// WebSocketRequest is the event received from an API Gateway WebSocket API.
+	WebSocketRequest = events.APIGatewayWebsocketProxyRequest
+	// WebSocketResponse is the response to an API Gateway WebSocket API. It is only sent to the client on $connect
+	// and on routes with a route response.
+	WebSocketResponse = events.APIGatewayProxyResponse
+)
+
+// WebSocketMessage is the request of the handlers registered with HandleMessage: the event and its body decoded as
+// JSON.
+type WebSocketMessage[Msg any] struct {
+	WebSocketRequest
+	Message Msg
+}
+
+// ConnectionID returns the ID of the connection that sent the message.
+func (m WebSocketMessage[Msg]) ConnectionID() string {
+	return m.RequestContext.ConnectionID
+}
+
+// MessageDecodeError is returned when the body of a WebSocket event cannot be decoded into the message type of its
+// route.
+type MessageDecodeError struct {
+	RouteKey string
+	Err      error
+}
+
+func (e *MessageDecodeError) Error() string {
+	return fmt.Sprintf("malformed message for route %s: %v", e.RouteKey, e.Err)
+}
+
+func (e *MessageDecodeError) Unwrap() error {
+	return e.Err
+}
+
+// WebSocketRouter dispatches the events of an API Gateway WebSocket API to handlers registered by route key. Events
+// whose route key is not registered are dispatched to the $default route.
+//
+//	r := lambda.NewWebSocketRouter()
+//	r.Handle(lambda.RouteConnect, onConnect)
+//	lambda.HandleMessage(r, "sendMessage", onSendMessage)
+//	lambda.StartWebSocket(r, lambda.WithConnectionPoster(poster))
+type WebSocketRouter struct {
+	routes      map[string]Handler[WebSocketRequest, WebSocketResponse]
+	middlewares []Middleware[WebSocketRequest, WebSocketResponse]
+}
+
+// NewWebSocketRouter creates an empty WebSocketRouter.
+func NewWebSocketRouter() *WebSocketRouter {
+	return &WebSocketRouter{
+		routes: make(map[string]Handler[WebSocketRequest, WebSocketResponse]),
+	}
+}
+
+// Use adds middlewares wrapping every route.
+func (r *WebSocketRouter) Use(middlewares ...Middleware[WebSocketRequest, WebSocketResponse]) *WebSocketRouter {
+	r.middlewares = append(r.middlewares, middlewares...)
+	return r
+}
+
+// Handle registers a handler for the given route key.
+func (r *WebSocketRouter) Handle(routeKey string, handler Handler[WebSocketRequest, WebSocketResponse], middlewares ...Middleware[WebSocketRequest, WebSocketResponse]) *WebSocketRouter {
+	if len(middlewares) > 0 {
+		handler = Use(handler, middlewares...)
+	}
+	r.routes[routeKey] = handler
+	return r
+}
+
+// HandleMessage registers, on the router, a handler receiving the body of the event decoded as JSON into Msg. A body
+// that cannot be decoded results in a MessageDecodeError.
+func HandleMessage[Msg any](r *WebSocketRouter, routeKey string, handler Handler[WebSocketMessage[Msg], WebSocketResponse], middlewares ...Middleware[WebSocketRequest, WebSocketResponse]) *WebSocketRouter {
+	return r.Handle(routeKey, func(ctx *Context[WebSocketRequest]) (WebSocketResponse, error) {
+		msg := WebSocketMessage[Msg]{WebSocketRequest: ctx.Request}
+		if ctx.Request.Body != "" {
+			if err := json.Unmarshal([]byte(ctx.Request.Body), &msg.Message); err != nil {
+				return WebSocketResponse{}, &MessageDecodeError{RouteKey: routeKey, Err: err}
+			}
+		}
+		return handler(&Context[WebSocketMessage[Msg]]{
+			Context: ctx.Context,
+			Request: msg,
+			Locals:  ctx.Locals,
+		})
+	}, middlewares...)
+}
+
+// Serve dispatches the event to the route matching its route key. It is a Handler, so it can be used with Start as
+// well.
+func (r *WebSocketRouter) Serve(ctx *Context[WebSocketRequest]) (WebSocketResponse, error) {
+	return Use(r.dispatch, r.middlewares...)(ctx)
+}
+
+func (r *WebSocketRouter) dispatch(ctx *Context[WebSocketRequest]) (WebSocketResponse, error) {
+	routeKey := ctx.Request.RequestContext.RouteKey
+	handler, ok := r.routes[routeKey]
+	if !ok {
+		handler, ok = r.routes[RouteDefault]
+	}
+	if !ok {
+		return WebSocketResponse{}, fmt.Errorf("%w: %s", ErrRouteNotFound, routeKey)
+	}
+	return handler(ctx)
+}
+
+// StartWebSocket will start the lambda function, as the integration of an API Gateway WebSocket API, with the given
+// router and options.
+//
+// Errors returned by the handlers are passed to the error handler set by WithErrorHandler, if any. Otherwise, they
+// fail the invocation.
+func StartWebSocket(router *WebSocketRouter, opts ...Option[WebSocketResponse]) {
+	c := defaultOpts[WebSocketResponse]()
+	for _, o := range opts {
+		o(&c)
+	}
+
+	manager := startManager(&c)
+
+	lambda.StartWithOptions(webSocketHandler(router, &c, manager), manager.LambdaOptions()...)
+}
+
+func webSocketHandler(router *WebSocketRouter, c *options[WebSocketResponse], manager *resources.Manager) func(context.Context, WebSocketRequest) (WebSocketResponse, error) {
+	return func(ctx context.Context, request WebSocketRequest) (WebSocketResponse, error) {
+		if err := manager.CheckReady(); err != nil {
+			return handleError(c, err)
+		}
+
+		ctx = resources.NewContext(ctx, manager)
+		if c.poster != nil {
+			ctx = ContextWithConnectionPoster(ctx, c.poster)
+		}
+		lambdaContext := Context[WebSocketRequest]{
+			Context: ctx,
+			Request: request,
+			Locals:  make(map[string]any),
+		}
+
+		resp, err := router.Serve(&lambdaContext)
+		if err != nil {
+			return handleError(c, err)
+		}
+		return resp, nil
+	}
+}
+
+func handleError[Resp any](c *options[Resp], err error) (Resp, error) {
+	if c.errorHandler == nil {
+		var resp Resp
+		return resp, err
+	}
+	return c.errorHandler(err)
+}
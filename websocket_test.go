@@ -0,0 +1,127 @@
+package lambda
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jamillosantos/lambda/internal/resources"
+)
+
+type chatMessage struct {
+	Text string `json:"text"`
+}
+
+func newWebSocketRequest(routeKey, connectionID, body string) WebSocketRequest {
+	return WebSocketRequest{
+		Body: body,
+		RequestContext: events.APIGatewayWebsocketProxyRequestContext{
+			RouteKey:     routeKey,
+			ConnectionID: connectionID,
+			DomainName:   "abc123.execute-api.us-east-1.amazonaws.com",
+			Stage:        "production",
+		},
+	}
+}
+
+func TestWebSocketRouter(t *testing.T) {
+	var seq []string
+	r := NewWebSocketRouter().Use(func(ctx *Context[WebSocketRequest], next Handler[WebSocketRequest, WebSocketResponse]) (WebSocketResponse, error) {
+		seq = append(seq, "middleware:"+ctx.Request.RequestContext.RouteKey)
+		return next(ctx)
+	})
+	r.Handle(RouteConnect, func(ctx *Context[WebSocketRequest]) (WebSocketResponse, error) {
+		return WebSocketResponse{StatusCode: http.StatusOK}, nil
+	})
+	r.Handle(RouteDefault, func(ctx *Context[WebSocketRequest]) (WebSocketResponse, error) {
+		return WebSocketResponse{StatusCode: http.StatusOK, Body: "default"}, nil
+	})
+	HandleMessage(r, "sendMessage", func(ctx *Context[WebSocketMessage[chatMessage]]) (WebSocketResponse, error) {
+		err := PostJSON(ctx.Context, ctx.Request.ConnectionID(), chatMessage{Text: "echo: " + ctx.Request.Message.Text})
+		return WebSocketResponse{StatusCode: http.StatusOK}, err
+	})
+
+	poster := NewMemoryPoster("conn-1")
+	c := defaultOpts[WebSocketResponse]()
+	WithConnectionPoster(poster)(&c)
+	h := webSocketHandler(r, &c, resources.NewStartedManager(nil))
+
+	t.Run("should dispatch by route key and wrap routes with the middlewares", func(t *testing.T) {
+		seq = nil
+		resp, err := h(context.Background(), newWebSocketRequest(RouteConnect, "conn-1", ""))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, []string{"middleware:$connect"}, seq)
+	})
+
+	t.Run("should decode the message and post to the connection", func(t *testing.T) {
+		_, err := h(context.Background(), newWebSocketRequest("sendMessage", "conn-1", `{"text":"hi"}`))
+		require.NoError(t, err)
+		assert.Equal(t, [][]byte{[]byte(`{"text":"echo: hi"}`)}, poster.Messages("conn-1"))
+	})
+
+	t.Run("should fall back to the default route", func(t *testing.T) {
+		resp, err := h(context.Background(), newWebSocketRequest("unknown", "conn-1", ""))
+		require.NoError(t, err)
+		assert.Equal(t, "default", resp.Body)
+	})
+
+	t.Run("should fail with MessageDecodeError for malformed messages", func(t *testing.T) {
+		_, err := h(context.Background(), newWebSocketRequest("sendMessage", "conn-1", `{"text":`))
+		var decodeErr *MessageDecodeError
+		require.ErrorAs(t, err, &decodeErr)
+		assert.Equal(t, "sendMessage", decodeErr.RouteKey)
+	})
+
+	t.Run("should fail posting to closed connections", func(t *testing.T) {
+		_, err := h(context.Background(), newWebSocketRequest("sendMessage", "conn-2", `{"text":"hi"}`))
+		require.ErrorIs(t, err, ErrGoneConnection)
+	})
+
+	t.Run("should pass errors to the error handler", func(t *testing.T) {
+		c := defaultOpts[WebSocketResponse]()
+		WithErrorHandler(func(err error) (WebSocketResponse, error) {
+			if errors.Is(err, ErrRouteNotFound) {
+				return WebSocketResponse{StatusCode: http.StatusNotFound}, nil
+			}
+			return WebSocketResponse{}, err
+		})(&c)
+		h := webSocketHandler(NewWebSocketRouter(), &c, resources.NewStartedManager(nil))
+
+		resp, err := h(context.Background(), newWebSocketRequest("unknown", "conn-1", ""))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
+
+func TestPostToConnection(t *testing.T) {
+	t.Run("should fail without poster", func(t *testing.T) {
+		require.ErrorIs(t, PostToConnection(context.Background(), "conn-1", nil), ErrNoConnectionPoster)
+	})
+
+	t.Run("should accept any connection when AcceptAll is set", func(t *testing.T) {
+		poster := NewMemoryPoster().AcceptAll()
+		ctx := ContextWithConnectionPoster(context.Background(), poster)
+		require.NoError(t, PostToConnection(ctx, "conn-9", []byte("hello")))
+		assert.Equal(t, [][]byte{[]byte("hello")}, poster.Messages("conn-9"))
+	})
+
+	t.Run("should reject connections after Disconnect", func(t *testing.T) {
+		poster := NewMemoryPoster("conn-1")
+		poster.Disconnect("conn-1")
+		ctx := ContextWithConnectionPoster(context.Background(), poster)
+		require.ErrorIs(t, PostToConnection(ctx, "conn-1", []byte("hello")), ErrGoneConnection)
+	})
+}
+
+func TestWebSocketEndpoint(t *testing.T) {
+	t.Run("should build the endpoint from the domain name and the stage", func(t *testing.T) {
+		endpoint := WebSocketEndpoint(newWebSocketRequest(RouteConnect, "conn-1", ""))
+		assert.Equal(t, "https://abc123.execute-api.us-east-1.amazonaws.com/production", endpoint)
+	})
+}